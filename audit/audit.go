@@ -0,0 +1,117 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package audit keeps a trail of who did what to which target and how it ended, apart from the
+// diagnostic log: every record goes to its own hash chained, append-only file through the audit
+// sink and encoders of package log, and the fields a record needs are parameters so leaving one
+// out does not compile:
+//
+//	trail, err := audit.Open(audit.Config{Path: "/var/log/sloan/audit.log", WORM: true})
+//	trail.Record(ctx, audit.Actor{ID: user}, "scan.start", audit.Target{Kind: "domain", ID: name}, audit.Success).Send()
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/osintami/sloan/log"
+)
+
+// Actor is who acted, IP is optional
+type Actor struct {
+	ID string
+	IP string
+}
+
+// Target is what was acted on, e.g. {Kind: "domain", ID: "example.com"}
+type Target struct {
+	Kind string
+	ID   string
+}
+
+// Action names what was done, e.g. "scan.start" or "export.download"
+type Action string
+
+// Outcome is how the action ended, one of Success, Failure and Denied
+type Outcome string
+
+const (
+	Success Outcome = "success"
+	Failure Outcome = "failure"
+	Denied  Outcome = "denied"
+)
+
+// Config of a trail: Path is required, the chain of every record to the one before is always on;
+// SigningKey, Encrypt, HMAC and WORM are those of log.AuditConfig and Rotation applies to Path
+type Config struct {
+	Path       string             `json:"path" yaml:"path" toml:"path"`
+	SigningKey string             `json:"signing_key" yaml:"signing_key" toml:"signing_key"`
+	Encrypt    string             `json:"encrypt" yaml:"encrypt" toml:"encrypt"`
+	HMAC       string             `json:"hmac" yaml:"hmac" toml:"hmac"`
+	WORM       bool               `json:"worm" yaml:"worm" toml:"worm"`
+	Rotation   log.RotationConfig `json:"rotation" yaml:"rotation" toml:"rotation"`
+}
+
+// Trail writes audit records to the dedicated sink of its own logger, nothing of the diagnostic
+// log reaches it and no record reaches the diagnostic log
+type Trail struct {
+	logger *log.Logger
+}
+
+// Open starts a trail, continuing the hash chain of an existing file
+func Open(cfg Config) (*Trail, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("audit trail needs a path")
+	}
+	// NOTE the logger has no sinks of its own, so only its reports of records with an empty
+	// required field go to stderr
+	logger, err := log.New(log.Config{
+		Rotation: cfg.Rotation,
+		Audit: log.AuditConfig{
+			Path:       cfg.Path,
+			Chain:      true,
+			SigningKey: cfg.SigningKey,
+			Encrypt:    cfg.Encrypt,
+			HMAC:       cfg.HMAC,
+			WORM:       cfg.WORM,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Trail{logger: logger}, nil
+}
+
+// Record starts the record of an action, add detail with the field methods and end it with Send
+// or Msg; the request ID of ctx is written so the record ties to the diagnostic entries of the
+// same request
+func (x *Trail) Record(ctx context.Context, actor Actor, action Action, target Target, outcome Outcome) log.ILogger {
+	e := x.logger.Audit(actor.ID, string(action), target.ID)
+	if actor.IP != "" {
+		e = e.Str("actor_ip", actor.IP)
+	}
+	if target.Kind != "" {
+		e = e.Str("target_kind", target.Kind)
+	}
+	e = e.Str("outcome", string(outcome))
+	if id := log.RequestID(ctx); id != "" {
+		e = e.Str("request_id", id)
+	}
+	return e
+}
+
+// Close flushes and closes the trail file
+func (x *Trail) Close(ctx context.Context) error {
+	return x.logger.Close(ctx)
+}
+
+// Verify checks the hash chain of a trail file and, when pub is given, the signature of every
+// record; an encrypted file is decrypted with the key provider of package log
+func Verify(path string, pub ed25519.PublicKey) (*log.ChainReport, error) {
+	r, err := log.OpenLog(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return log.VerifyChain(r, pub)
+}
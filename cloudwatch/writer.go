@@ -0,0 +1,254 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package cloudwatch ships the log stream to AWS CloudWatch Logs, it lives apart from package
+// log so only services that ship to CloudWatch pull in the AWS SDK
+package cloudwatch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/osintami/sloan/log"
+)
+
+// NOTE PutLogEvents takes at most MAX_EVENTS events and MAX_BYTES, counting EVENT_OVERHEAD
+// bytes per event on top of the message, and a single message of at most MAX_EVENT_BYTES;
+// longer entries are cut there so one entry can never block the stream
+const (
+	MAX_EVENTS      = 10000
+	MAX_BYTES       = 1048576
+	MAX_EVENT_BYTES = 262144 - EVENT_OVERHEAD
+	EVENT_OVERHEAD  = 26
+	BATCH_WAIT      = 5 * time.Second
+	BUFFER          = 8192
+	RETRIES         = 5
+	BACKOFF_MIN     = 200 * time.Millisecond
+	CLOSE_TIMEOUT   = 10 * time.Second
+)
+
+// Config names the group and stream, both are created when missing; Stream defaults to
+// "{service}/{hostname}", Region to the region of the credential chain (AWS_REGION in Lambda
+// and ECS) and RetentionDays, set on a group this writer creates, to never expire
+type Config struct {
+	Group         string `json:"group" yaml:"group" toml:"group"`
+	Stream        string `json:"stream" yaml:"stream" toml:"stream"`
+	Region        string `json:"region" yaml:"region" toml:"region"`
+	RetentionDays int32  `json:"retention_days" yaml:"retention_days" toml:"retention_days"`
+	BatchWait     string `json:"batch_wait" yaml:"batch_wait" toml:"batch_wait"`
+}
+
+// Writer puts every line written to it as one log event from a background goroutine, hand it
+// to log.AddWriter and Close it after the logger so the last batch is sent
+type Writer struct {
+	client    *cloudwatchlogs.Client
+	group     string
+	stream    string
+	wait      time.Duration
+	token     *string // the sequence token of the next put, nil for a new stream
+	ch        chan types.InputLogEvent
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	failed    atomic.Uint64
+	mu        sync.Mutex
+	lastErr   error
+}
+
+// NewWriter resolves credentials through the default chain, environment, shared config, the
+// ECS task role or the Lambda execution role, and creates the group and stream
+func NewWriter(ctx context.Context, cfg Config) (*Writer, error) {
+	if cfg.Group == "" {
+		return nil, fmt.Errorf("cloudwatch: group is required")
+	}
+	options := []func(*config.LoadOptions) error{}
+	if cfg.Region != "" {
+		options = append(options, config.WithRegion(cfg.Region))
+	}
+	shared, err := config.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("cloudwatch: %w", err)
+	}
+	x := &Writer{client: cloudwatchlogs.NewFromConfig(shared), group: cfg.Group, stream: cfg.Stream, wait: BATCH_WAIT,
+		ch: make(chan types.InputLogEvent, BUFFER), done: make(chan struct{}), stopped: make(chan struct{})}
+	if x.stream == "" {
+		fields := log.StandardFields("")
+		x.stream = fields["service"] + "/" + fields["hostname"]
+	}
+	if cfg.BatchWait != "" {
+		if x.wait, err = time.ParseDuration(cfg.BatchWait); err != nil || x.wait <= 0 {
+			return nil, fmt.Errorf("cloudwatch: batch_wait %q is not a positive duration", cfg.BatchWait)
+		}
+	}
+	if err := x.create(ctx, cfg.RetentionDays); err != nil {
+		return nil, err
+	}
+	go x.run()
+	return x, nil
+}
+
+// create makes the group and stream, an existing one is not an error
+func (x *Writer) create(ctx context.Context, retentionDays int32) error {
+	var exists *types.ResourceAlreadyExistsException
+	_, err := x.client.CreateLogGroup(ctx, &cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String(x.group)})
+	switch {
+	case err == nil && retentionDays > 0:
+		if _, err := x.client.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{LogGroupName: aws.String(x.group), RetentionInDays: aws.Int32(retentionDays)}); err != nil {
+			return fmt.Errorf("cloudwatch: retention of %s: %w", x.group, err)
+		}
+	case err != nil && !errors.As(err, &exists):
+		return fmt.Errorf("cloudwatch: create group %s: %w", x.group, err)
+	}
+	_, err = x.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{LogGroupName: aws.String(x.group), LogStreamName: aws.String(x.stream)})
+	if err != nil && !errors.As(err, &exists) {
+		return fmt.Errorf("cloudwatch: create stream %s: %w", x.stream, err)
+	}
+	return nil
+}
+
+// Write queues each line of p as an event stamped with the time of its entry, a full buffer
+// drops the line and counts it in Failed
+func (x *Writer) Write(p []byte) (int, error) {
+	select {
+	case <-x.done:
+		return 0, errors.New("cloudwatch writer closed")
+	default:
+	}
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		stamp := time.Now()
+		if entry, err := log.ParseEntry(line); err == nil && !entry.Time.IsZero() {
+			stamp = entry.Time
+		}
+		if len(line) > MAX_EVENT_BYTES {
+			line = line[:MAX_EVENT_BYTES]
+		}
+		select {
+		case x.ch <- types.InputLogEvent{Message: aws.String(string(line)), Timestamp: aws.Int64(stamp.UnixMilli())}:
+		default:
+			x.fail(1, errors.New("buffer full"))
+		}
+	}
+	return len(p), nil
+}
+
+func (x *Writer) run() {
+	defer close(x.stopped)
+	pending, size := []types.InputLogEvent{}, 0
+	timer := time.NewTimer(x.wait)
+	timer.Stop()
+	flush := func(retry bool) {
+		x.put(pending, retry)
+		pending, size = pending[:0], 0
+	}
+	for {
+		select {
+		case event := <-x.ch:
+			cost := len(*event.Message) + EVENT_OVERHEAD
+			if len(pending) == MAX_EVENTS || size+cost > MAX_BYTES {
+				timer.Stop()
+				flush(true)
+			}
+			if len(pending) == 0 {
+				timer.Reset(x.wait)
+			}
+			pending, size = append(pending, event), size+cost
+		case <-timer.C:
+			flush(true)
+		case <-x.done:
+			timer.Stop()
+			for len(x.ch) > 0 {
+				event := <-x.ch
+				cost := len(*event.Message) + EVENT_OVERHEAD
+				if len(pending) == MAX_EVENTS || size+cost > MAX_BYTES {
+					flush(false)
+				}
+				pending, size = append(pending, event), size+cost
+			}
+			flush(false)
+			return
+		}
+	}
+}
+
+// put sends one batch, following the expected sequence token when the stream has moved on
+// and retrying throttling and network errors with backoff while retry is set
+func (x *Writer) put(events []types.InputLogEvent, retry bool) {
+	if len(events) == 0 {
+		return
+	}
+	// NOTE events of a batch must be in chronological order
+	sort.SliceStable(events, func(i, j int) bool { return *events[i].Timestamp < *events[j].Timestamp })
+	backoff := BACKOFF_MIN
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), CLOSE_TIMEOUT)
+		out, err := x.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName: aws.String(x.group), LogStreamName: aws.String(x.stream), LogEvents: events, SequenceToken: x.token,
+		})
+		cancel()
+		var invalid *types.InvalidSequenceTokenException
+		var accepted *types.DataAlreadyAcceptedException
+		switch {
+		case err == nil:
+			x.token = out.NextSequenceToken
+			if out.RejectedLogEventsInfo != nil {
+				x.fail(0, fmt.Errorf("events rejected as too old, too new or expired: %+v", *out.RejectedLogEventsInfo))
+			}
+			return
+		case errors.As(err, &accepted):
+			x.token = accepted.ExpectedSequenceToken
+			return
+		case errors.As(err, &invalid):
+			x.token = invalid.ExpectedSequenceToken
+			if attempt < RETRIES {
+				continue
+			}
+		}
+		if !retry || attempt >= RETRIES {
+			x.fail(len(events), err)
+			return
+		}
+		select {
+		case <-x.done:
+			retry = false
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (x *Writer) fail(count int, err error) {
+	x.failed.Add(uint64(count))
+	x.mu.Lock()
+	x.lastErr = err
+	x.mu.Unlock()
+}
+
+// Failed is the number of entries CloudWatch did not take and the last error
+func (x *Writer) Failed() (uint64, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.failed.Load(), x.lastErr
+}
+
+// Close sends what is pending, waiting up to CLOSE_TIMEOUT
+func (x *Writer) Close() error {
+	x.closeOnce.Do(func() { close(x.done) })
+	select {
+	case <-x.stopped:
+		return nil
+	case <-time.After(CLOSE_TIMEOUT):
+		return errors.New("cloudwatch writer: timed out sending pending entries")
+	}
+}
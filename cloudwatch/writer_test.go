@@ -0,0 +1,122 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package cloudwatch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// fakeLogs answers the CloudWatch Logs JSON API: the group already exists and the first put
+// gets the sequence token wrong
+type fakeLogs struct {
+	mu      sync.Mutex
+	calls   []string
+	tokens  []string
+	events  [][]string
+	created map[string]string
+}
+
+func (x *fakeLogs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	action := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "Logs_20140328.")
+	x.calls = append(x.calls, action)
+	body, _ := io.ReadAll(r.Body)
+	input := struct {
+		LogGroupName  string `json:"logGroupName"`
+		LogStreamName string `json:"logStreamName"`
+		SequenceToken string `json:"sequenceToken"`
+		LogEvents     []struct {
+			Message   string `json:"message"`
+			Timestamp int64  `json:"timestamp"`
+		} `json:"logEvents"`
+	}{}
+	json.Unmarshal(body, &input)
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	switch action {
+	case "CreateLogGroup":
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"__type":"ResourceAlreadyExistsException","message":"exists"}`)
+	case "CreateLogStream":
+		x.created[input.LogGroupName] = input.LogStreamName
+		io.WriteString(w, `{}`)
+	case "PutLogEvents":
+		x.tokens = append(x.tokens, input.SequenceToken)
+		if input.SequenceToken == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"__type":"InvalidSequenceTokenException","message":"wrong token","expectedSequenceToken":"t1"}`)
+			return
+		}
+		messages := []string{}
+		for _, event := range input.LogEvents {
+			messages = append(messages, event.Message)
+		}
+		x.events = append(x.events, messages)
+		io.WriteString(w, `{"nextSequenceToken":"t2"}`)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"__type":"UnknownOperationException"}`)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	fake := &fakeLogs{created: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	for key, value := range map[string]string{
+		"AWS_ENDPOINT_URL": server.URL, "AWS_REGION": "us-east-1", "AWS_ACCESS_KEY_ID": "AKIDTEST", "AWS_SECRET_ACCESS_KEY": "secret",
+		"AWS_CONFIG_FILE": "/dev/null", "AWS_SHARED_CREDENTIALS_FILE": "/dev/null", "AWS_EC2_METADATA_DISABLED": "true",
+	} {
+		t.Setenv(key, value)
+	}
+	x, err := NewWriter(context.Background(), Config{Group: "/app/api", Stream: "api/host1", BatchWait: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.Write([]byte(`{"time":"2025-03-09T12:00:02Z","message":"late"}` + "\n" + `{"time":"2025-03-09T12:00:01Z","message":"early"}` + "\n"))
+	if err := x.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.created["/app/api"] != "api/host1" {
+		t.Fatalf("created streams: %v", fake.created)
+	}
+	// NOTE the rejected token is followed without waiting
+	if len(fake.tokens) != 2 || fake.tokens[1] != "t1" || x.token == nil || *x.token != "t2" {
+		t.Fatalf("sequence tokens: %v", fake.tokens)
+	}
+	if len(fake.events) != 1 || !strings.Contains(fake.events[0][0], "early") || !strings.Contains(fake.events[0][1], "late") {
+		t.Fatalf("events are not in chronological order: %v", fake.events)
+	}
+	if failed, err := x.Failed(); failed != 0 {
+		t.Fatalf("failed: %d %v", failed, err)
+	}
+}
+
+func TestWriterCutsLongEntries(t *testing.T) {
+	x := &Writer{ch: make(chan types.InputLogEvent, 1), done: make(chan struct{})}
+	x.Write([]byte(strings.Repeat("x", MAX_EVENT_BYTES+100) + "\n"))
+	if event := <-x.ch; len(*event.Message) != MAX_EVENT_BYTES {
+		t.Fatalf("message of %d bytes", len(*event.Message))
+	}
+	x.Write([]byte("one\ntwo\n"))
+	if failed, err := x.Failed(); failed != 1 || err == nil {
+		t.Fatalf("a full buffer should count: %d %v", failed, err)
+	}
+}
+
+func TestNewWriterNeedsGroup(t *testing.T) {
+	if _, err := NewWriter(context.Background(), Config{}); err == nil {
+		t.Fatal("accepted a config without a group")
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// sloan-anonymize rewrites sloan logs with secrets masked and IP addresses hashed so they can be shared
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/osintami/sloan/log"
+)
+
+func main() {
+	config := flag.String("config", "", "sloan config file whose redact rules are applied")
+	keys := flag.String("keys", "", "extra comma separated field keys to mask")
+	patterns := flag.String("patterns", "", "extra ';' separated regexes to mask")
+	hashIPs := flag.Bool("hash-ips", true, "replace IP addresses with a salted hash")
+	salt := flag.String("salt", "", "salt for IP hashes, random when empty so hashes only correlate within one run")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: sloan-anonymize [flags] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	rules := log.RedactConfig{}
+	if *config != "" {
+		cfg, err := log.LoadConfig(*config)
+		if err != nil {
+			fatal(err)
+		}
+		rules = cfg.Redact
+	}
+	if *keys != "" {
+		rules.Keys = append(rules.Keys, strings.Split(*keys, ",")...)
+	}
+	if *patterns != "" {
+		rules.Patterns = append(rules.Patterns, strings.Split(*patterns, ";")...)
+	}
+	if *salt == "" {
+		random := make([]byte, 16)
+		rand.Read(random)
+		*salt = hex.EncodeToString(random)
+	}
+
+	anonymizer, err := log.NewAnonymizer(rules, *hashIPs, *salt)
+	if err != nil {
+		fatal(err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	if flag.NArg() == 0 {
+		run(anonymizer, os.Stdin, out)
+		return
+	}
+	for _, name := range flag.Args() {
+		fh, err := log.OpenLog(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] open failed", err)
+			continue
+		}
+		run(anonymizer, fh, out)
+		fh.Close()
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "[ERROR]", err)
+	os.Exit(2)
+}
+
+// run drops lines that are not JSON since they cannot be anonymized field by field
+func run(anonymizer *log.Anonymizer, r io.Reader, out io.Writer) {
+	decoder := log.NewDecoder(r)
+	for {
+		entry, err := decoder.Decode()
+		if err == io.EOF {
+			return
+		}
+		if errors.Is(err, log.ErrMalformedEntry) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] read failed", err)
+			return
+		}
+		out.Write(anonymizer.Entry(entry).Raw)
+		out.Write([]byte("\n"))
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// sloan-export converts sloan log files into CSV or TSV rows for spreadsheets and DuckDB
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/osintami/sloan/log"
+)
+
+func main() {
+	format := flag.String("format", "csv", "output format: csv or tsv")
+	columns := flag.String("columns", "time,level,message", "columns as name=field or field, comma separated")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: sloan-export [flags] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	sources := []log.EntrySource{}
+	if flag.NArg() == 0 {
+		sources = append(sources, log.NewDecoder(os.Stdin))
+	}
+	for _, name := range flag.Args() {
+		fh, err := log.OpenLog(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] open failed", err)
+			os.Exit(1)
+		}
+		defer fh.Close()
+		sources = append(sources, log.NewDecoder(fh))
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	if _, err := log.Export(log.NewMerger(sources...), out, *format, log.ParseColumns(*columns)); err != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] export failed", err)
+		out.Flush()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// sloan-merge interleaves sloan log files into one chronologically ordered stream
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/osintami/sloan/log"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: sloan-merge file ...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	sources := []log.EntrySource{}
+	for _, name := range flag.Args() {
+		fh, err := log.OpenLog(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] open failed", err)
+			os.Exit(1)
+		}
+		defer fh.Close()
+		sources = append(sources, log.NewDecoder(fh))
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	merger := log.NewMerger(sources...)
+	for {
+		entry, err := merger.Decode()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] merge failed", err)
+			return
+		}
+		out.Write(entry.Raw)
+		out.WriteString("\n")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// sloan-pretty renders sloan NDJSON logs from stdin or files as aligned, colorized lines
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/osintami/sloan/internal/tail"
+	"github.com/osintami/sloan/log"
+)
+
+const (
+	COLOR_RESET  = "\x1b[0m"
+	COLOR_RED    = "\x1b[31m"
+	COLOR_YELLOW = "\x1b[33m"
+	COLOR_GREEN  = "\x1b[32m"
+	COLOR_BLUE   = "\x1b[34m"
+	COLOR_GRAY   = "\x1b[90m"
+	COLOR_BOLD   = "\x1b[1m"
+)
+
+type printer struct {
+	out     *bufio.Writer
+	fields  map[string]bool
+	color   bool
+	follow  bool
+	decrypt bool
+}
+
+func main() {
+	fields := flag.String("fields", "", "comma separated fields to show, default all")
+	noColor := flag.Bool("no-color", false, "disable ANSI colors")
+	decrypt := flag.Bool("decrypt", false, "decrypt encrypted fields with keys from SLOAN_KEY_<ID>")
+	var follow bool
+	flag.BoolVar(&follow, "f", false, "follow the file as it grows, across rotations")
+	flag.BoolVar(&follow, "follow", false, "same as -f")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: sloan-pretty [flags] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	x := &printer{out: bufio.NewWriter(os.Stdout), color: !*noColor && isTerminal(os.Stdout), follow: follow, decrypt: *decrypt}
+	defer x.out.Flush()
+	if *fields != "" {
+		x.fields = map[string]bool{}
+		for _, name := range strings.Split(*fields, ",") {
+			x.fields[strings.TrimSpace(name)] = true
+		}
+	}
+
+	if follow {
+		if flag.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "[ERROR] --follow requires exactly one file")
+			os.Exit(2)
+		}
+		fh, err := tail.Follow(flag.Arg(0), true)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] follow failed", err)
+			os.Exit(1)
+		}
+		x.render(fh)
+		return
+	}
+	if flag.NArg() == 0 {
+		x.render(os.Stdin)
+		return
+	}
+	for _, name := range flag.Args() {
+		fh, err := log.OpenLog(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] open failed", err)
+			continue
+		}
+		x.render(fh)
+		fh.Close()
+	}
+}
+
+func isTerminal(fh *os.File) bool {
+	info, err := fh.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (x *printer) render(r io.Reader) {
+	decoder := log.NewDecoder(r)
+	for {
+		entry, err := decoder.Decode()
+		if err == io.EOF {
+			return
+		}
+		if errors.Is(err, log.ErrMalformedEntry) {
+			x.out.Write(entry.Raw)
+			x.out.WriteString("\n")
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] read failed", err)
+			return
+		} else {
+			x.line(entry)
+		}
+		if x.follow {
+			x.out.Flush()
+		}
+	}
+}
+
+func (x *printer) line(entry *log.Entry) {
+	if x.decrypt {
+		if err := entry.Decrypt(); err != nil {
+			fmt.Fprintln(os.Stderr, "[WARN] decrypt failed", err)
+		}
+	}
+	stamp := ""
+	if !entry.Time.IsZero() {
+		stamp = entry.Time.Format(time.RFC3339)
+	}
+	x.paint(COLOR_GRAY, stamp)
+	x.out.WriteString(" ")
+	x.paint(levelColor(entry.Level), fmt.Sprintf("%-5s", strings.ToUpper(entry.Level)))
+	x.out.WriteString(" ")
+	x.paint(COLOR_BOLD, fmt.Sprintf("%-40s", entry.Message))
+
+	keys := []string{}
+	for key := range entry.Fields {
+		if x.fields == nil || x.fields[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		x.out.WriteString(" ")
+		x.paint(COLOR_BLUE, key+"=")
+		x.out.WriteString(format(entry.Fields[key]))
+	}
+	x.out.WriteString("\n")
+}
+
+func (x *printer) paint(color, text string) {
+	if !x.color {
+		x.out.WriteString(text)
+		return
+	}
+	x.out.WriteString(color + text + COLOR_RESET)
+}
+
+func levelColor(level string) string {
+	switch level {
+	case "fatal", "error":
+		return COLOR_RED
+	case "warn":
+		return COLOR_YELLOW
+	case "info":
+		return COLOR_GREEN
+	}
+	return COLOR_GRAY
+}
+
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, " \t\"=") {
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// sloan-query prints the sloan NDJSON entries that match time, level and field filters
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/osintami/sloan/internal/tail"
+	"github.com/osintami/sloan/log"
+)
+
+var LEVELS = map[string]int{"trace": 0, "debug": 1, "info": 2, "warn": 3, "error": 4, "fatal": 5}
+
+type condition struct {
+	key   string
+	value string
+	re    *regexp.Regexp
+}
+
+type conditions []condition
+
+func (x *conditions) String() string {
+	return fmt.Sprint(*x)
+}
+
+// Set accepts key=value for an exact match and key~regex for a pattern match
+func (x *conditions) Set(text string) error {
+	if key, pattern, ok := strings.Cut(text, "~"); ok && !strings.Contains(key, "=") {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		*x = append(*x, condition{key: key, re: re})
+		return nil
+	}
+	key, value, ok := strings.Cut(text, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value or key~regex, got %q", text)
+	}
+	*x = append(*x, condition{key: key, value: value})
+	return nil
+}
+
+type query struct {
+	since time.Time
+	until time.Time
+	level int
+	where conditions
+	flush *bufio.Writer
+}
+
+func main() {
+	x := &query{level: -1}
+	since := flag.String("since", "", "only entries at or after this RFC3339 time or duration ago (e.g. 1h)")
+	until := flag.String("until", "", "only entries before this RFC3339 time or duration ago")
+	level := flag.String("level", "", "minimum level: trace, debug, info, warn, error or fatal")
+	flag.Var(&x.where, "where", "field filter key=value or key~regex, repeatable")
+	var follow bool
+	flag.BoolVar(&follow, "f", false, "follow the file as it grows, across rotations")
+	flag.BoolVar(&follow, "follow", false, "same as -f")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: sloan-query [flags] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var err error
+	if x.since, err = parseTime(*since); err != nil {
+		fatal("--since", err)
+	}
+	if x.until, err = parseTime(*until); err != nil {
+		fatal("--until", err)
+	}
+	if *level != "" {
+		rank, ok := LEVELS[strings.ToLower(*level)]
+		if !ok {
+			fatal("--level", fmt.Errorf("unknown level %q", *level))
+		}
+		x.level = rank
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	if follow {
+		if flag.NArg() != 1 {
+			fatal("--follow", fmt.Errorf("requires exactly one file"))
+		}
+		fh, err := tail.Follow(flag.Arg(0), true)
+		if err != nil {
+			fatal("--follow", err)
+		}
+		x.flush = out
+		x.run(log.NewDecoder(fh), out)
+		return
+	}
+	if flag.NArg() == 0 {
+		x.run(log.NewDecoder(os.Stdin), out)
+		return
+	}
+	for _, name := range flag.Args() {
+		reader, err := log.OpenIndexed(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] open failed", err)
+			continue
+		}
+		decoder, err := reader.Range(x.since, x.until)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] seek failed", err)
+		} else {
+			x.run(decoder, out)
+		}
+		reader.Close()
+	}
+}
+
+func fatal(flag string, err error) {
+	fmt.Fprintf(os.Stderr, "[ERROR] %s: %s\n", flag, err)
+	os.Exit(2)
+}
+
+func parseTime(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(text); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, text)
+}
+
+func (x *query) run(decoder log.EntrySource, out io.Writer) {
+	for {
+		entry, err := decoder.Decode()
+		if err == io.EOF {
+			return
+		}
+		if errors.Is(err, log.ErrMalformedEntry) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] read failed", err)
+			return
+		}
+		if x.match(entry) {
+			out.Write(entry.Raw)
+			out.Write([]byte("\n"))
+			if x.flush != nil {
+				x.flush.Flush()
+			}
+		}
+	}
+}
+
+func (x *query) match(entry *log.Entry) bool {
+	if !x.since.IsZero() || !x.until.IsZero() {
+		if entry.Time.IsZero() {
+			return false
+		}
+		if !x.since.IsZero() && entry.Time.Before(x.since) {
+			return false
+		}
+		if !x.until.IsZero() && !entry.Time.Before(x.until) {
+			return false
+		}
+	}
+	if x.level >= 0 {
+		if rank, ok := LEVELS[entry.Level]; !ok || rank < x.level {
+			return false
+		}
+	}
+	for _, cond := range x.where {
+		text, ok := entry.Field(cond.key)
+		if !ok {
+			return false
+		}
+		if cond.re != nil && !cond.re.MatchString(text) {
+			return false
+		}
+		if cond.re == nil && text != cond.value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// sloan-stats prints a JSON summary of sloan log files: counts per level, component and time bucket
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/osintami/sloan/log"
+)
+
+func main() {
+	bucket := flag.Duration("bucket", 0, "time bucket size (e.g. 1h), 0 disables buckets")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: sloan-stats [flags] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	stats := log.NewStats(*bucket)
+	var err error
+	if flag.NArg() == 0 {
+		err = stats.Read(os.Stdin)
+	} else {
+		stats, err = log.StatsFromFiles(*bucket, flag.Args()...)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] stats failed", err)
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(stats)
+}
@@ -0,0 +1,123 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// sloan-verify checks the hash chain and ed25519 signatures of sloan audit logs, or with -worm
+// that no rotated file of a WORM log was deleted or modified, or with -hmac the HMAC of rotated files
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/osintami/sloan/log"
+)
+
+func main() {
+	keyFile := flag.String("pubkey", "", "file with the hex or base64 ed25519 public key, signatures are required when set")
+	genkey := flag.String("genkey", "", "write a new key pair to <prefix>.key and <prefix>.pub and exit")
+	worm := flag.Bool("worm", false, "check the WORM manifest of each live log file instead of its chain")
+	seal := flag.Bool("hmac", false, "check each rotated file against its .hmac file, the key comes from the KeyProvider")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: sloan-verify [flags] file ...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *genkey != "" {
+		if err := generateKey(*genkey); err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] genkey", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var pub ed25519.PublicKey
+	if *keyFile != "" {
+		var err error
+		if pub, err = log.LoadVerifyKey(*keyFile); err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] public key", err)
+			os.Exit(2)
+		}
+	}
+
+	failed := false
+	encoder := json.NewEncoder(os.Stdout)
+	if *seal {
+		for _, name := range flag.Args() {
+			ok, err := log.VerifyHMAC(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "[ERROR] hmac failed", name, err)
+				failed = true
+				continue
+			}
+			failed = failed || !ok
+			encoder.Encode(struct {
+				File string `json:"file"`
+				OK   bool   `json:"ok"`
+			}{name, ok})
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
+	}
+	if *worm {
+		for _, name := range flag.Args() {
+			report, err := log.VerifyWORM(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "[ERROR] read failed", name, err)
+				failed = true
+				continue
+			}
+			failed = failed || !report.OK
+			encoder.Encode(struct {
+				Path string `json:"path"`
+				*log.WORMReport
+			}{name, report})
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
+	}
+	for _, name := range flag.Args() {
+		fh, err := log.OpenLog(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] open failed", err)
+			failed = true
+			continue
+		}
+		report, err := log.VerifyChain(fh, pub)
+		fh.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] read failed", name, err)
+			failed = true
+			continue
+		}
+		failed = failed || !report.OK
+		encoder.Encode(struct {
+			File string `json:"file"`
+			*log.ChainReport
+		}{name, report})
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func generateKey(prefix string) error {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(prefix+".key", []byte(hex.EncodeToString(priv.Seed())+"\n"), 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(prefix+".pub", []byte(hex.EncodeToString(pub)+"\n"), 0644)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package errors carries a code and fields with an error so context gathered deep in the call
+// stack reaches the log, log.Err writes them as entry fields without string concatenation
+package errors
+
+import "errors"
+
+type Error struct {
+	code   string
+	msg    string
+	fields map[string]interface{}
+	cause  error
+}
+
+// New returns an error with a machine readable code, e.g. "scan.timeout"
+func New(code, msg string) *Error {
+	return &Error{code: code, msg: msg, fields: map[string]interface{}{}}
+}
+
+// Wrap adds a code and message to err, fields of a wrapped *Error are kept
+func Wrap(err error, code, msg string) *Error {
+	x := New(code, msg)
+	x.cause = err
+	return x
+}
+
+// With adds a field, an outer error wins over a wrapped one with the same key
+func (x *Error) With(key string, value interface{}) *Error {
+	x.fields[key] = value
+	return x
+}
+
+func (x *Error) Error() string {
+	if x.cause == nil {
+		return x.msg
+	}
+	return x.msg + ": " + x.cause.Error()
+}
+
+func (x *Error) Unwrap() error {
+	return x.cause
+}
+
+func (x *Error) Code() string {
+	return x.code
+}
+
+// Fields merges the fields of every *Error in the chain
+func (x *Error) Fields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	var inner *Error
+	if errors.As(x.cause, &inner) {
+		fields = inner.Fields()
+	}
+	for key, value := range x.fields {
+		fields[key] = value
+	}
+	return fields
+}
+
+// Code returns the code of the outermost *Error in err, or ""
+func Code(err error) string {
+	var x *Error
+	if errors.As(err, &x) {
+		return x.code
+	}
+	return ""
+}
+
+// Fields returns the fields of every *Error in err, or nil
+func Fields(err error) map[string]interface{} {
+	var x *Error
+	if errors.As(err, &x) {
+		return x.Fields()
+	}
+	return nil
+}
+
+// Is, As and Unwrap forward to the standard library so this package can replace its import
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
@@ -0,0 +1,247 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package grpc logs gRPC calls with server and client interceptors, it lives apart from package
+// log so only services that speak gRPC pull in the gRPC module:
+//
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(sloangrpc.UnaryServerInterceptor(log.Default())),
+//		grpc.ChainStreamInterceptor(sloangrpc.StreamServerInterceptor(log.Default())),
+//	)
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osintami/sloan/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// NOTE the request ID travels in this metadata key, a client forwards the one in its context
+// and a server makes one up with log.NEW_REQUEST_ID when the caller sent none or an invalid one
+const REQUEST_ID_KEY = "x-request-id"
+
+// Options tune the interceptors: Skip lists full method names not logged, e.g.
+// "/grpc.health.v1.Health/Check"; Metadata writes the metadata of the call, with the values of
+// authorization and the Redact keys written as log.REDACT_MASK
+type Options struct {
+	Skip     []string
+	Metadata bool
+	Redact   []string
+}
+
+type interceptor struct {
+	logger *log.Logger
+	skip   map[string]bool
+	redact map[string]bool
+	meta   bool
+}
+
+func newInterceptor(logger *log.Logger, options []Options) *interceptor {
+	cfg := Options{}
+	if len(options) > 0 {
+		cfg = options[0]
+	}
+	// NOTE Redact adds to authorization, listing other keys must not write the credentials
+	x := &interceptor{logger: logger, skip: map[string]bool{}, redact: map[string]bool{"authorization": true}, meta: cfg.Metadata}
+	for _, method := range cfg.Skip {
+		x.skip[method] = true
+	}
+	for _, key := range cfg.Redact {
+		x.redact[strings.ToLower(key)] = true
+	}
+	return x
+}
+
+// UnaryServerInterceptor writes one entry per call with its method, code, duration, peer and
+// request ID; the handler context carries a logger with the method and the request ID, so
+// log.Ctx(ctx) in handlers writes both
+func UnaryServerInterceptor(logger *log.Logger, options ...Options) grpc.UnaryServerInterceptor {
+	x := newInterceptor(logger, options)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if x.skip[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		ctx, done := x.server(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streams, the entry is written when the
+// stream ends
+func StreamServerInterceptor(logger *log.Logger, options ...Options) grpc.StreamServerInterceptor {
+	x := newInterceptor(logger, options)
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if x.skip[info.FullMethod] {
+			return handler(srv, stream)
+		}
+		ctx, done := x.server(stream.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+		done(err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor writes one entry per call with its method, code, duration and target,
+// and forwards the request ID of ctx, see log.WithRequestID
+func UnaryClientInterceptor(logger *log.Logger, options ...Options) grpc.UnaryClientInterceptor {
+	x := newInterceptor(logger, options)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if x.skip[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		ctx, done := x.client(ctx, method, cc.Target())
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		done(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor is UnaryClientInterceptor for streams, the entry is written when the
+// stream ends or fails to open
+func StreamClientInterceptor(logger *log.Logger, options ...Options) grpc.StreamClientInterceptor {
+	x := newInterceptor(logger, options)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if x.skip[method] {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		ctx, done := x.client(ctx, method, cc.Target())
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			done(err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: stream, done: done}, nil
+	}
+}
+
+func (x *interceptor) server(ctx context.Context, method string) (context.Context, func(error)) {
+	start := time.Now()
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := first(md, REQUEST_ID_KEY)
+	if !log.ValidRequestID(id) {
+		id = log.NEW_REQUEST_ID()
+	}
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remote = p.Addr.String()
+	}
+	logger := x.logger.With().Str("method", method).Logger()
+	ctx = log.WithRequestID(logger.WithContext(ctx), id)
+	return ctx, func(err error) {
+		x.write(logger, md, err, start).
+			Str("peer", remote).
+			Str("request_id", id).
+			Msg("call")
+	}
+}
+
+func (x *interceptor) client(ctx context.Context, method, target string) (context.Context, func(error)) {
+	start := time.Now()
+	id := log.RequestID(ctx)
+	if id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, REQUEST_ID_KEY, id)
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	logger := x.logger.With().Str("method", method).Logger()
+	return ctx, func(err error) {
+		e := x.write(logger, md, err, start).Str("target", target)
+		if id != "" {
+			e = e.Str("request_id", id)
+		}
+		e.Msg("call")
+	}
+}
+
+// write starts the entry of a finished call at the level of its code
+func (x *interceptor) write(logger *log.Logger, md metadata.MD, err error, start time.Time) log.ILogger {
+	code := status.Code(err)
+	var e log.ILogger
+	switch level(code) {
+	case log.ErrorLevel:
+		e = logger.Error()
+	case log.WarnLevel:
+		e = logger.Warn()
+	default:
+		e = logger.Info()
+	}
+	e = e.Str("code", code.String()).Dur("duration", time.Since(start))
+	if x.meta && len(md) > 0 {
+		dict := logger.Dict()
+		for key, values := range md {
+			// NOTE binary values are not text, they are left out rather than mangled
+			if strings.HasSuffix(key, "-bin") {
+				continue
+			}
+			if x.redact[key] {
+				values = []string{log.REDACT_MASK}
+			}
+			dict = dict.Strs(key, values)
+		}
+		e = e.Dict("metadata", dict)
+	}
+	if err != nil {
+		e = e.Err(err)
+	}
+	return e
+}
+
+// level is info for OK, warn for codes the caller is to blame for and error for the rest
+func level(code codes.Code) log.Level {
+	switch code {
+	case codes.OK:
+		return log.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return log.WarnLevel
+	}
+	return log.ErrorLevel
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// serverStream hands the handler the context with the request scoped logger
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (x *serverStream) Context() context.Context {
+	return x.ctx
+}
+
+// clientStream writes the entry once, when RecvMsg reports the end of the stream
+type clientStream struct {
+	grpc.ClientStream
+	done func(error)
+	once sync.Once
+}
+
+func (x *clientStream) RecvMsg(m interface{}) error {
+	err := x.ClientStream.RecvMsg(m)
+	if err != nil {
+		x.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				x.done(nil)
+				return
+			}
+			x.done(err)
+		})
+	}
+	return err
+}
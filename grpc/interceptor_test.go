@@ -0,0 +1,112 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/osintami/sloan/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// calls returns a logger writing at info and the "call" entries it writes
+func calls(t *testing.T) (*log.Logger, <-chan log.Entry) {
+	t.Helper()
+	logger, err := log.New(log.Config{Level: "info", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logger.Close(context.Background()) })
+	entries, unsubscribe := log.Subscribe(func(x *log.Entry) bool { return x.Message == "call" })
+	t.Cleanup(unsubscribe)
+	return logger, entries
+}
+
+func received(t *testing.T, entries <-chan log.Entry) log.Entry {
+	t.Helper()
+	select {
+	case entry := <-entries:
+		return entry
+	case <-time.After(time.Second):
+		t.Fatal("no call entry")
+	}
+	return log.Entry{}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	logger, entries := calls(t)
+	interceptor := UnaryServerInterceptor(logger, Options{Metadata: true, Redact: []string{"x-api-key"}})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer hunter2", "x-api-key", "hunter3", "x-tenant", "acme", REQUEST_ID_KEY, "req-1"))
+	var handled string
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/items.Items/Get"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled = log.RequestID(ctx)
+		return nil, status.Error(codes.NotFound, "no such item")
+	})
+	if status.Code(err) != codes.NotFound || handled != "req-1" {
+		t.Fatalf("handler: %v %q", err, handled)
+	}
+
+	entry := received(t, entries)
+	if entry.Level != "warn" || entry.Fields["code"] != "NotFound" || entry.Fields["method"] != "/items.Items/Get" || entry.Fields["request_id"] != "req-1" {
+		t.Fatalf("call entry: %s", entry.Raw)
+	}
+	// NOTE authorization stays masked when Redact lists other keys
+	md := entry.Fields["metadata"].(map[string]interface{})
+	for key, want := range map[string]string{"authorization": log.REDACT_MASK, "x-api-key": log.REDACT_MASK, "x-tenant": "acme"} {
+		if values := md[key].([]interface{}); values[0] != want {
+			t.Fatalf("%s: expected %s in %s", key, want, entry.Raw)
+		}
+	}
+}
+
+func TestUnaryServerInterceptorSkips(t *testing.T) {
+	logger, entries := calls(t)
+	interceptor := UnaryServerInterceptor(logger, Options{Skip: []string{"/grpc.health.v1.Health/Check"}})
+	interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	select {
+	case entry := <-entries:
+		t.Fatalf("logged a skipped method: %s", entry.Raw)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	logger, entries := calls(t)
+	conn, err := grpc.NewClient("passthrough:///items:443", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	interceptor := UnaryClientInterceptor(logger)
+	ctx := log.WithRequestID(context.Background(), "req-2")
+	var forwarded string
+	err = interceptor(ctx, "/items.Items/Get", nil, nil, conn, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		forwarded = first(md, REQUEST_ID_KEY)
+		return status.Error(codes.Unavailable, "down")
+	})
+	if forwarded != "req-2" {
+		t.Fatalf("request id sent: %q", forwarded)
+	}
+	entry := received(t, entries)
+	if entry.Level != "error" || entry.Fields["code"] != "Unavailable" || entry.Fields["target"] != "passthrough:///items:443" || entry.Fields["request_id"] != "req-2" {
+		t.Fatalf("call entry: %s", entry.Raw)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	cases := map[codes.Code]log.Level{codes.OK: log.InfoLevel, codes.PermissionDenied: log.WarnLevel, codes.Internal: log.ErrorLevel, codes.DeadlineExceeded: log.ErrorLevel}
+	for code, want := range cases {
+		if got := level(code); got != want {
+			t.Fatalf("%s: expected %s, got %s", code, want, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package tail
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// Follower reads a file like tail -f, when the path is rotated away or truncated it finishes
+// the old file and continues from the start of the new one
+type Follower struct {
+	Path string
+	Poll time.Duration
+	fh   *os.File
+}
+
+// Follow opens path and positions at the end when fromEnd is set, otherwise at the start
+func Follow(path string, fromEnd bool) (*Follower, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if fromEnd {
+		if _, err := fh.Seek(0, io.SeekEnd); err != nil {
+			fh.Close()
+			return nil, err
+		}
+	}
+	return &Follower{Path: path, Poll: 250 * time.Millisecond, fh: fh}, nil
+}
+
+// Read blocks until data is available, it never returns io.EOF
+func (x *Follower) Read(p []byte) (int, error) {
+	for {
+		n, err := x.fh.Read(p)
+		if n > 0 || (err != nil && err != io.EOF) {
+			return n, err
+		}
+		if x.rotated() {
+			continue
+		}
+		time.Sleep(x.Poll)
+	}
+}
+
+// rotated swaps to the file now at Path when it is a different inode or the old one shrank
+func (x *Follower) rotated() bool {
+	info, err := os.Stat(x.Path)
+	if err != nil {
+		return false
+	}
+	current, err := x.fh.Stat()
+	if err != nil {
+		return false
+	}
+	offset, _ := x.fh.Seek(0, io.SeekCurrent)
+	if os.SameFile(info, current) {
+		if info.Size() < offset {
+			x.fh.Seek(0, io.SeekStart)
+			return true
+		}
+		return false
+	}
+	fh, err := os.Open(x.Path)
+	if err != nil {
+		return false
+	}
+	x.fh.Close()
+	x.fh = fh
+	return true
+}
+
+func (x *Follower) Close() error {
+	return x.fh.Close()
+}
@@ -0,0 +1,158 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package kafka produces the log stream to a Kafka topic, it lives apart from package log so
+// only services that ship to Kafka pull in the client library
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/osintami/sloan/log"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// NOTE a batch is produced when BATCH_SIZE entries are pending or the oldest has waited
+// BATCH_WAIT, whichever comes first
+const (
+	BATCH_SIZE = 1000
+	BATCH_WAIT = time.Second
+)
+
+var ACKS = map[string]kafka.RequiredAcks{
+	"all":  kafka.RequireAll,
+	"one":  kafka.RequireOne,
+	"none": kafka.RequireNone,
+}
+
+var COMPRESSION = map[string]kafka.Compression{
+	"none":   0,
+	"gzip":   kafka.Gzip,
+	"snappy": kafka.Snappy,
+	"lz4":    kafka.Lz4,
+	"zstd":   kafka.Zstd,
+}
+
+// Config selects the topic and how entries are produced: Key is the entry field whose value
+// picks the partition, "service" (the default) keeps a service on one partition and
+// "trace_id" keeps a trace together, entries without it fall back to the service name; Acks is
+// all (the default), one or none and Compression none, gzip, snappy (the default), lz4 or zstd
+type Config struct {
+	Brokers     []string `json:"brokers" yaml:"brokers" toml:"brokers"`
+	Topic       string   `json:"topic" yaml:"topic" toml:"topic"`
+	Key         string   `json:"key" yaml:"key" toml:"key"`
+	Acks        string   `json:"acks" yaml:"acks" toml:"acks"`
+	Compression string   `json:"compression" yaml:"compression" toml:"compression"`
+	BatchSize   int      `json:"batch_size" yaml:"batch_size" toml:"batch_size"`
+	BatchWait   string   `json:"batch_wait" yaml:"batch_wait" toml:"batch_wait"`
+}
+
+// Writer produces every line written to it as one message, hand it to log.AddWriter and Close
+// it after the logger so the last batch is acknowledged before the process exits
+type Writer struct {
+	producer *kafka.Writer
+	key      string
+	service  string
+	failed   atomic.Uint64
+	mu       sync.Mutex
+	lastErr  error
+}
+
+func NewWriter(cfg Config) (*Writer, error) {
+	if len(cfg.Brokers) == 0 || cfg.Topic == "" {
+		return nil, fmt.Errorf("kafka: brokers and topic are required")
+	}
+	acks, ok := ACKS[strings.ToLower(cfg.Acks)]
+	if cfg.Acks == "" {
+		acks, ok = kafka.RequireAll, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("kafka: unknown acks %q, use all, one or none", cfg.Acks)
+	}
+	compression, ok := COMPRESSION[strings.ToLower(cfg.Compression)]
+	if cfg.Compression == "" {
+		compression, ok = kafka.Snappy, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("kafka: unknown compression %q, use none, gzip, snappy, lz4 or zstd", cfg.Compression)
+	}
+	wait := BATCH_WAIT
+	if cfg.BatchWait != "" {
+		var err error
+		if wait, err = time.ParseDuration(cfg.BatchWait); err != nil || wait <= 0 {
+			return nil, fmt.Errorf("kafka: batch_wait %q is not a positive duration", cfg.BatchWait)
+		}
+	}
+	size := cfg.BatchSize
+	if size <= 0 {
+		size = BATCH_SIZE
+	}
+	x := &Writer{key: cfg.Key, service: log.StandardFields("")["service"]}
+	if x.key == "" {
+		x.key = "service"
+	}
+	// NOTE Async keeps Write off the network, failures arrive in Completion
+	x.producer = &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: acks,
+		Compression:  compression,
+		BatchSize:    size,
+		BatchTimeout: wait,
+		Async:        true,
+		Completion:   x.completion,
+	}
+	return x, nil
+}
+
+// Write produces each line of p, the async queue of a sink hands over several at once
+func (x *Writer) Write(p []byte) (int, error) {
+	messages := []kafka.Message{}
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		messages = append(messages, kafka.Message{Key: x.partitionKey(line), Value: append([]byte{}, line...)})
+	}
+	if err := x.producer.WriteMessages(context.Background(), messages...); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (x *Writer) partitionKey(line []byte) []byte {
+	if entry, err := log.ParseEntry(line); err == nil {
+		if value, ok := entry.Field(x.key); ok && value != "" {
+			return []byte(value)
+		}
+	}
+	return []byte(x.service)
+}
+
+func (x *Writer) completion(messages []kafka.Message, err error) {
+	if err == nil {
+		return
+	}
+	x.failed.Add(uint64(len(messages)))
+	x.mu.Lock()
+	x.lastErr = err
+	x.mu.Unlock()
+}
+
+// Failed is the number of entries the brokers did not acknowledge and the last error
+func (x *Writer) Failed() (uint64, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.failed.Load(), x.lastErr
+}
+
+// Close produces what is still batched and waits for the brokers to acknowledge it
+func (x *Writer) Close() error {
+	return x.producer.Close()
+}
@@ -0,0 +1,59 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package kafka
+
+import (
+	"errors"
+	"testing"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+func TestNewWriter(t *testing.T) {
+	x, err := NewWriter(Config{Brokers: []string{"kafka:9092"}, Topic: "logs", Acks: "one", Compression: "ZSTD", BatchSize: 10, BatchWait: "50ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer x.Close()
+	if x.producer.RequiredAcks != kafka.RequireOne || x.producer.Compression != kafka.Zstd || x.producer.BatchSize != 10 || x.key != "service" {
+		t.Fatalf("producer: %+v", x.producer)
+	}
+}
+
+func TestNewWriterRefusesBadConfig(t *testing.T) {
+	cases := map[string]Config{
+		"no brokers":  {Topic: "logs"},
+		"no topic":    {Brokers: []string{"kafka:9092"}},
+		"acks":        {Brokers: []string{"kafka:9092"}, Topic: "logs", Acks: "most"},
+		"compression": {Brokers: []string{"kafka:9092"}, Topic: "logs", Compression: "brotli"},
+		"batch wait":  {Brokers: []string{"kafka:9092"}, Topic: "logs", BatchWait: "0s"},
+	}
+	for name, cfg := range cases {
+		if _, err := NewWriter(cfg); err == nil {
+			t.Fatalf("%s: accepted %+v", name, cfg)
+		}
+	}
+}
+
+func TestPartitionKey(t *testing.T) {
+	x := &Writer{key: "trace_id", service: "api"}
+	cases := map[string]string{
+		`{"trace_id":"4bf92f35","message":"one"}`: "4bf92f35",
+		`{"trace_id":"","message":"two"}`:         "api",
+		`{"message":"three"}`:                     "api",
+		`not json`:                                "api",
+	}
+	for line, key := range cases {
+		if got := string(x.partitionKey([]byte(line))); got != key {
+			t.Fatalf("%s: expected key %s, got %s", line, key, got)
+		}
+	}
+}
+
+func TestFailedCountsUnacknowledged(t *testing.T) {
+	x := &Writer{}
+	x.completion(make([]kafka.Message, 3), nil)
+	x.completion(make([]kafka.Message, 2), errors.New("leader not available"))
+	if failed, err := x.Failed(); failed != 2 || err == nil {
+		t.Fatalf("failed: %d %v", failed, err)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// ServeAdmin listens on a unix socket for one command per line and answers with one JSON line:
+//
+//	set-level <level> [component]   change the global or a component level
+//	rotate                          roll over every file sink
+//	reopen                          open every file again after an external rotation
+//	flush                           sync every file sink to disk
+//	stats                           report the running logger state
+//	health                          report the state of every sink
+//	goroutines                      log the stack of every goroutine
+//	dry-run                         report what each sink of a dry run config would have written
+func ServeAdmin(path string) (net.Listener, error) {
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveAdmin(conn)
+		}
+	}()
+	return listener, nil
+}
+
+func serveAdmin(conn net.Conn) {
+	defer conn.Close()
+	encoder := json.NewEncoder(conn)
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		result, err := adminCommand(fields[0], fields[1:])
+		if err != nil {
+			encoder.Encode(map[string]interface{}{"ok": false, "error": err.Error()})
+			continue
+		}
+		encoder.Encode(map[string]interface{}{"ok": true, "result": result})
+		Info().Str("component", "osintami").Str("command", scanner.Text()).Msg("admin command")
+	}
+}
+
+func adminCommand(command string, args []string) (interface{}, error) {
+	switch command {
+	case "set-level":
+		if len(args) != 1 && len(args) != 2 {
+			return nil, fmt.Errorf("usage: set-level <level> [component]")
+		}
+		if len(args) == 2 {
+			return args, setLevelName(Component(args[1]), args[0])
+		}
+		return args[0], setLevelName(std, args[0])
+	case "rotate":
+		return nil, Rotate()
+	case "reopen":
+		return nil, Reopen()
+	case "flush":
+		return nil, Flush()
+	case "stats":
+		return adminStats(), nil
+	case "health":
+		return Health(), nil
+	case "goroutines":
+		return DumpGoroutines(), nil
+	case "dry-run":
+		return DryRunReport(), nil
+	}
+	return nil, fmt.Errorf("unknown command %q, use set-level, rotate, reopen, flush, stats, health, goroutines or dry-run", command)
+}
+
+func adminStats() map[string]interface{} {
+	st := active()
+	sinks := []string{}
+	for _, item := range st.sinks {
+		sinks = append(sinks, item.name)
+	}
+	return map[string]interface{}{
+		"level":    levelName(std.mask()),
+		"sinks":    sinks,
+		"sequence": sequence.Load(),
+		"metrics":  ReadMetrics(),
+	}
+}
+
+// setLevelName changes the level of logger until a config is applied
+func setLevelName(logger *Logger, name string) error {
+	level, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	logger.SetLevel(level)
+	return nil
+}
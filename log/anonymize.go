@@ -0,0 +1,122 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DEFAULT_SECRET_PATTERNS catch common credentials that show up in free text
+var DEFAULT_SECRET_PATTERNS = []string{
+	`(?i)bearer\s+[a-z0-9._~+/=-]+`,
+	`AKIA[0-9A-Z]{16}`,
+	`(?i)(password|passwd|secret|token|api_?key)=[^\s&"]+`,
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----`,
+}
+
+var ipPattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|\b(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}\b`)
+
+// Anonymizer rewrites decoded entries so logs can be shared: redaction rules mask secrets and
+// IP addresses are replaced by a salted hash that stays stable for the same salt
+type Anonymizer struct {
+	redact  *redactor
+	salt    []byte
+	hashIPs bool
+}
+
+// NewAnonymizer adds DEFAULT_SECRET_PATTERNS and the registered secret rules to the given rules
+func NewAnonymizer(cfg RedactConfig, hashIPs bool, salt string) (*Anonymizer, error) {
+	registered := SecretRules()
+	cfg.Keys = append(append([]string{}, cfg.Keys...), registered.Keys...)
+	cfg.Patterns = append(append([]string{}, cfg.Patterns...), DEFAULT_SECRET_PATTERNS...)
+	cfg.Patterns = append(cfg.Patterns, registered.Patterns...)
+	redact, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Anonymizer{redact: redact, salt: []byte(salt), hashIPs: hashIPs}, nil
+}
+
+func (x *Anonymizer) Entry(entry *Entry) *Entry {
+	entry.Message = x.text(entry.Message)
+	for key, value := range entry.Fields {
+		entry.Fields[key] = x.value(key, value)
+	}
+	entry.Raw = x.encode(entry)
+	return entry
+}
+
+func (x *Anonymizer) value(key string, value interface{}) interface{} {
+	if x.redact.keys[strings.ToLower(key)] {
+		return x.redact.mask
+	}
+	switch v := value.(type) {
+	case string:
+		return x.text(v)
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = x.value(k, item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = x.value("", item)
+		}
+	}
+	return value
+}
+
+func (x *Anonymizer) text(value string) string {
+	value = x.redact.text(value)
+	if x.hashIPs {
+		value = ipPattern.ReplaceAllStringFunc(value, x.hashIP)
+	}
+	return value
+}
+
+func (x *Anonymizer) hashIP(ip string) string {
+	mac := hmac.New(sha256.New, x.salt)
+	mac.Write([]byte(ip))
+	return "ip_" + hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// encode writes time and level first, fields sorted by key, then the message
+func (x *Anonymizer) encode(entry *Entry) []byte {
+	var buffer bytes.Buffer
+	cfg := active().config
+	buffer.WriteString("{")
+	if !entry.Time.IsZero() {
+		buffer.Write(appendTimestamp(appendKey(nil, cfg.timeKey()), cfg, entry.Time))
+		buffer.WriteString(",")
+	}
+	buffer.Write(appendKey(nil, cfg.levelKey()))
+	buffer.Write(marshal(entry.Level))
+	keys := []string{}
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		buffer.WriteString(",")
+		buffer.Write(marshal(key))
+		buffer.WriteString(":")
+		buffer.Write(marshal(entry.Fields[key]))
+	}
+	buffer.Write(appendKey([]byte{','}, cfg.messageKey()))
+	buffer.Write(marshal(entry.Message))
+	buffer.WriteString("}")
+	return buffer.Bytes()
+}
+
+func marshal(value interface{}) []byte {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return []byte("null")
+	}
+	return data
+}
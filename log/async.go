@@ -0,0 +1,186 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// NOTE ASYNC_BUFFER is the queue length per sink when AsyncConfig.Buffer is 0, ASYNC_BATCH caps
+// the lines joined into one write
+const (
+	ASYNC_BUFFER = 4096
+	ASYNC_BATCH  = 256
+)
+
+// AsyncConfig queues entries per sink for a background writer so logging never waits on a slow
+// disk or network; Policy "block" (the default) waits for room when a queue is full, "drop"
+// discards the entry and counts it in Metrics.Dropped; audit and fatal entries always wait, and
+// the audit file is always written right away
+type AsyncConfig struct {
+	Enabled bool   `json:"enabled" yaml:"enabled" toml:"enabled"`
+	Buffer  int    `json:"buffer" yaml:"buffer" toml:"buffer"`
+	Policy  string `json:"policy" yaml:"policy" toml:"policy"`
+}
+
+type asyncItem struct {
+	line  []byte
+	level int
+	done  chan struct{}
+}
+
+type asyncQueue struct {
+	sink    *sink
+	ch      chan asyncItem
+	drop    bool
+	mu      sync.RWMutex
+	closed  bool
+	stopped chan struct{}
+}
+
+func newAsyncQueue(x *sink, cfg AsyncConfig) *asyncQueue {
+	size := cfg.Buffer
+	if size <= 0 {
+		size = ASYNC_BUFFER
+	}
+	q := &asyncQueue{sink: x, ch: make(chan asyncItem, size), drop: cfg.Policy == "drop", stopped: make(chan struct{})}
+	go q.run()
+	return q
+}
+
+// enqueue reports false once the queue is closed, the caller then writes the line itself
+func (x *asyncQueue) enqueue(line []byte, level int, keep bool) bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if x.closed {
+		return false
+	}
+	if !x.drop || keep {
+		x.ch <- asyncItem{line: line, level: level}
+		return true
+	}
+	select {
+	case x.ch <- asyncItem{line: line, level: level}:
+	default:
+		metricDropped.Add(1)
+		x.sink.dropped.Add(1)
+	}
+	return true
+}
+
+func (x *asyncQueue) run() {
+	defer close(x.stopped)
+	for item := range x.ch {
+		batch := []asyncItem{item}
+	collect:
+		for len(batch) < ASYNC_BATCH {
+			select {
+			case next, ok := <-x.ch:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, next)
+			default:
+				break collect
+			}
+		}
+		x.write(batch)
+	}
+}
+
+// write joins the batch into one write, except for a RotatingFile and a LevelWriter which expect
+// one line per call
+func (x *asyncQueue) write(batch []asyncItem) {
+	_, perLine := x.sink.w.(*RotatingFile)
+	if _, ok := x.sink.w.(LevelWriter); ok {
+		perLine = true
+	}
+	joined := []byte{}
+	for _, item := range batch {
+		if item.line == nil {
+			continue
+		}
+		if perLine {
+			x.sink.write(item.line, item.level)
+		} else {
+			joined = append(joined, item.line...)
+		}
+	}
+	if len(joined) > 0 {
+		x.sink.write(joined, LOG_TRACE)
+	}
+	for _, item := range batch {
+		if item.done != nil {
+			close(item.done)
+		}
+	}
+}
+
+// flush waits until every line queued before it is written
+func (x *asyncQueue) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	x.mu.RLock()
+	if x.closed {
+		x.mu.RUnlock()
+		done = x.stopped
+	} else {
+		select {
+		case x.ch <- asyncItem{done: done}:
+			x.mu.RUnlock()
+		case <-ctx.Done():
+			x.mu.RUnlock()
+			return ctx.Err()
+		}
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close writes what is queued and stops the writer
+func (x *asyncQueue) close() {
+	x.mu.Lock()
+	if !x.closed {
+		x.closed = true
+		close(x.ch)
+	}
+	x.mu.Unlock()
+	<-x.stopped
+}
+
+func (x *asyncQueue) Backlog() int {
+	return len(x.ch)
+}
+
+// summarize writes the counts of Throttle when x is the state of the default logger and those
+// of the samplers of the config and its components
+func (x *state) summarize() {
+	if x == std.active() {
+		flushThrottles()
+	}
+	x.sample.flush()
+	for _, item := range x.components {
+		item.sample.flush()
+	}
+}
+
+// drain writes the counts of summarize and the entries held by dedup, then waits for every queue
+// of the state to be written, or for ctx
+func (x *state) drain(ctx context.Context) error {
+	if x == nil {
+		return nil
+	}
+	x.summarize()
+	x.dedup.flush()
+	errs := []error{}
+	for _, item := range x.all() {
+		if item.queue != nil {
+			errs = append(errs, item.queue.flush(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
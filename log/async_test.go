@@ -0,0 +1,80 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// asyncLogger writes info and above to a file in a temporary directory through a queue of buffer
+func asyncLogger(t *testing.T, buffer int) (*Logger, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "a.log")
+	logger, err := New(Config{Level: "info", Sinks: []SinkConfig{{Type: "file", Path: path}}, Async: AsyncConfig{Enabled: true, Buffer: buffer}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logger, path
+}
+
+// messages returns the message of every line of the file but the startup entry
+func messages(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := []string{}
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		entry, err := ParseEntry(line)
+		if err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+		if entry.Message != "startup" {
+			list = append(list, entry.Message)
+		}
+	}
+	return list
+}
+
+func TestAsyncFlushWritesInOrder(t *testing.T) {
+	logger, path := asyncLogger(t, 16)
+	defer logger.Close(context.Background())
+	for i := 0; i < 1000; i++ {
+		logger.Info().Msg(strconv.Itoa(i))
+	}
+	if err := logger.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	list := messages(t, path)
+	if len(list) != 1000 {
+		t.Fatalf("expected 1000 entries after Flush, got %d", len(list))
+	}
+	for i, message := range list {
+		if message != strconv.Itoa(i) {
+			t.Fatalf("entry %d is %s", i, message)
+		}
+	}
+}
+
+func TestAsyncCloseDrainsQueue(t *testing.T) {
+	logger, path := asyncLogger(t, 0)
+	for i := 0; i < 500; i++ {
+		logger.Info().Msg("queued")
+	}
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if list := messages(t, path); len(list) != 500 {
+		t.Fatalf("expected 500 entries after Close, got %d", len(list))
+	}
+	// NOTE entries after Close are dropped, not written to a closed file
+	logger.Info().Msg("late")
+	if list := messages(t, path); len(list) != 500 {
+		t.Fatalf("entry written after Close: %d", len(list))
+	}
+}
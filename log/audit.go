@@ -0,0 +1,53 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+// AuditConfig sends audit entries to their own file, when Path is empty they go to the regular sinks
+type AuditConfig struct {
+	Path  string `json:"path" yaml:"path" toml:"path"`
+	Chain bool   `json:"chain" yaml:"chain" toml:"chain"`
+	// SigningKey is the path of an ed25519 private key, signing implies Chain
+	SigningKey string `json:"signing_key" yaml:"signing_key" toml:"signing_key"`
+	Encrypt    string `json:"encrypt" yaml:"encrypt" toml:"encrypt"`
+	WORM       bool   `json:"worm" yaml:"worm" toml:"worm"`
+	HMAC       string `json:"hmac" yaml:"hmac" toml:"hmac"`
+}
+
+// Audit records a compliance relevant action, it is written regardless of LOG_LEVEL, sampling
+// and component overrides; an empty actor, action or target is logged as "unknown" and reported
+func Audit(actor, action, target string) ILogger {
+	return std.Audit(actor, action, target)
+}
+
+func (x *Logger) Audit(actor, action, target string) ILogger {
+	e := newEvent(x, x.active(), LOG_FATAL)
+	e.audit = true
+	e.appendLevel("audit")
+	e.buf = append(append(e.buf, e.st.static...), x.fields...)
+	missing := []string{}
+	for _, field := range [][2]string{{"actor", actor}, {"action", action}, {"target", target}} {
+		if field[1] == "" {
+			missing = append(missing, field[0])
+			field[1] = "unknown"
+		}
+		e.Str(field[0], field[1])
+	}
+	for _, name := range missing {
+		x.Error().Str("component", "audit").Str("field", name).Str("action", action).Msg("audit entry missing required field")
+	}
+	return e
+}
+
+func (x *Event) writeAudit(st *state, out []byte) {
+	countEntry(x, out)
+	if st.audit != nil {
+		writeSinks([]*sink{st.audit}, x.level, out)
+		publish(out)
+		return
+	}
+	if x.logger.legacy {
+		writeLegacy(out)
+	}
+	writeSinks(st.sinks, x.level, out)
+	writeSinks(x.logger.extra(), x.level, out)
+	publish(out)
+}
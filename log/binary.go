@@ -0,0 +1,180 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// NOTE cbor (RFC 8949) and msgpack entries are one map each, written back to back; top level
+// fields keep their order, nested objects are written with sorted keys
+
+func encodeCBOR(line []byte) []byte {
+	keys, values, ok := orderedFields(line)
+	if !ok {
+		return line
+	}
+	buf := cborHead(nil, 5, uint64(len(keys)))
+	for _, key := range keys {
+		buf = appendCBOR(appendCBOR(buf, key), decodeValue(values[key]))
+	}
+	return buf
+}
+
+func cborHead(dst []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(dst, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(dst, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, major|27), n)
+}
+
+func appendCBOR(dst []byte, value interface{}) []byte {
+	switch value := value.(type) {
+	case bool:
+		if value {
+			return append(dst, 0xf5)
+		}
+		return append(dst, 0xf4)
+	case string:
+		return append(cborHead(dst, 3, uint64(len(value))), value...)
+	case json.Number:
+		if n, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			if n < 0 {
+				return cborHead(dst, 1, uint64(-1-n))
+			}
+			return cborHead(dst, 0, uint64(n))
+		}
+		if n, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return cborHead(dst, 0, n)
+		}
+		f, _ := value.Float64()
+		return binary.BigEndian.AppendUint64(append(dst, 0xfb), math.Float64bits(f))
+	case []interface{}:
+		dst = cborHead(dst, 4, uint64(len(value)))
+		for _, item := range value {
+			dst = appendCBOR(dst, item)
+		}
+		return dst
+	case map[string]interface{}:
+		dst = cborHead(dst, 5, uint64(len(value)))
+		for _, key := range sortedKeys(value) {
+			dst = appendCBOR(appendCBOR(dst, key), value[key])
+		}
+		return dst
+	}
+	return append(dst, 0xf6)
+}
+
+func encodeMsgpack(line []byte) []byte {
+	keys, values, ok := orderedFields(line)
+	if !ok {
+		return line
+	}
+	buf := msgpackHead(nil, 0x80, 0xde, uint64(len(keys)), 16)
+	for _, key := range keys {
+		buf = appendMsgpack(appendMsgpack(buf, key), decodeValue(values[key]))
+	}
+	return buf
+}
+
+// msgpackHead writes a fix type below limit, then the 16 and 32 bit forms that follow wide
+func msgpackHead(dst []byte, fix, wide byte, n uint64, limit uint64) []byte {
+	switch {
+	case n < limit:
+		return append(dst, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, wide), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(dst, wide+1), uint32(n))
+}
+
+func appendMsgpack(dst []byte, value interface{}) []byte {
+	switch value := value.(type) {
+	case bool:
+		if value {
+			return append(dst, 0xc3)
+		}
+		return append(dst, 0xc2)
+	case string:
+		if len(value) >= 32 && len(value) <= math.MaxUint8 {
+			return append(append(dst, 0xd9, byte(len(value))), value...)
+		}
+		return append(msgpackHead(dst, 0xa0, 0xda, uint64(len(value)), 32), value...)
+	case json.Number:
+		if n, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			switch {
+			case n >= 0 && n < 128, n < 0 && n >= -32:
+				return append(dst, byte(n))
+			case n >= 0:
+				return msgpackUint(dst, uint64(n))
+			case n >= math.MinInt8:
+				return append(dst, 0xd0, byte(n))
+			case n >= math.MinInt16:
+				return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(n))
+			case n >= math.MinInt32:
+				return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(n))
+			}
+			return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(n))
+		}
+		if n, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return msgpackUint(dst, n)
+		}
+		f, _ := value.Float64()
+		return binary.BigEndian.AppendUint64(append(dst, 0xcb), math.Float64bits(f))
+	case []interface{}:
+		dst = msgpackHead(dst, 0x90, 0xdc, uint64(len(value)), 16)
+		for _, item := range value {
+			dst = appendMsgpack(dst, item)
+		}
+		return dst
+	case map[string]interface{}:
+		dst = msgpackHead(dst, 0x80, 0xde, uint64(len(value)), 16)
+		for _, key := range sortedKeys(value) {
+			dst = appendMsgpack(appendMsgpack(dst, key), value[key])
+		}
+		return dst
+	}
+	return append(dst, 0xc0)
+}
+
+func msgpackUint(dst []byte, n uint64) []byte {
+	switch {
+	case n <= math.MaxUint8:
+		return append(dst, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, 0xcf), n)
+}
+
+// decodeValue keeps numbers as json.Number so integers stay integers
+func decodeValue(raw json.RawMessage) interface{} {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	decoder.Decode(&value)
+	return value
+}
+
+func sortedKeys(value map[string]interface{}) []string {
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
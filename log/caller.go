@@ -0,0 +1,69 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Caller annotates this event with the calling file and line whatever Config.Caller says, skip
+// moves further up for helpers that wrap the logger
+func (x *Event) Caller(skip ...int) ILogger {
+	if x.ignore || x.caller {
+		return x
+	}
+	frames := 2
+	for _, n := range skip {
+		frames += n
+	}
+	x.buf = appendCaller(x.buf, x.st.config, frames)
+	x.caller = true
+	return x
+}
+
+// Skip moves the caller Config.Caller writes up by frames, for adapters that wrap the logger
+// such as the logr sink
+func (x *Event) Skip(frames int) *Event {
+	if !x.ignore {
+		x.depth += frames
+	}
+	return x
+}
+
+// appendCaller writes "caller" and, with Config.CallerFunc, "function" for the frame skip levels
+// above it
+func appendCaller(dst []byte, cfg Config, skip int) []byte {
+	pc, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return appendString(appendKey(append(dst, ','), "caller"), "???")
+	}
+	function := ""
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		function = fn.Name()
+	}
+	return appendSite(dst, cfg, file, line, function)
+}
+
+// appendFrame writes the caller of a program counter from runtime.Callers, e.g. one handed over by slog
+func appendFrame(dst []byte, cfg Config, pc uintptr) []byte {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return appendSite(dst, cfg, frame.File, frame.Line, frame.Function)
+}
+
+func appendSite(dst []byte, cfg Config, file string, line int, function string) []byte {
+	dst = appendString(appendKey(append(dst, ','), "caller"), callerPath(file, cfg.CallerTrim)+":"+strconv.Itoa(line))
+	if cfg.CallerFunc && function != "" {
+		dst = appendString(appendKey(append(dst, ','), "function"), function)
+	}
+	return dst
+}
+
+// callerPath trims the prefix from file, or keeps the last directory and the file name
+func callerPath(file, trim string) string {
+	if trim != "" && strings.HasPrefix(file, trim) {
+		return strings.TrimPrefix(file[len(trim):], "/")
+	}
+	return filepath.Base(filepath.Dir(file)) + "/" + filepath.Base(file)
+}
@@ -0,0 +1,227 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// NOTE a chained line ends with ,"chain":"<hex>"} where chain = sha256(previous chain || line
+// without the chain field), the first line of a file chains from 32 zero bytes; a signed line
+// also ends with ,"sig":"<base64>" which is the ed25519 signature of the raw chain bytes
+var chainSuffix = regexp.MustCompile(`,"chain":"([0-9a-f]{64})"(?:,"sig":"([A-Za-z0-9+/]+={0,2})")?}$`)
+
+// errChainBroken is why lastChain cannot continue a file, a plain one or one whose last line
+// was torn by a crash
+var errChainBroken = errors.New("last line carries no chain")
+
+func chainHash(prev, content []byte) []byte {
+	h := sha256.New()
+	h.Write(prev)
+	h.Write(content)
+	return h.Sum(nil)
+}
+
+// appendChain adds the chain field, and the signature when key is set, to a line ending in "}\n"
+// and returns it with the new chain
+func appendChain(line, prev []byte, key ed25519.PrivateKey) ([]byte, []byte) {
+	content := bytes.TrimRight(line, "\n")
+	chain := chainHash(prev, content)
+	out := make([]byte, 0, len(content)+180)
+	out = append(out, content[:len(content)-1]...)
+	out = append(out, `,"chain":"`...)
+	out = append(out, hex.EncodeToString(chain)...)
+	if key != nil {
+		out = append(out, `","sig":"`...)
+		out = append(out, base64.StdEncoding.EncodeToString(ed25519.Sign(key, chain))...)
+	}
+	out = append(out, "\"}\n"...)
+	return out, chain
+}
+
+// LoadSigningKey reads a hex or base64 ed25519 private key, either the 32 byte seed or the 64 byte key
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	key, err := readKey(path)
+	if err != nil {
+		return nil, err
+	}
+	switch len(key) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key), nil
+	}
+	return nil, fmt.Errorf("signing key %s: expected %d or %d bytes, got %d", path, ed25519.SeedSize, ed25519.PrivateKeySize, len(key))
+}
+
+// LoadVerifyKey reads a hex or base64 ed25519 public key
+func LoadVerifyKey(path string) (ed25519.PublicKey, error) {
+	key, err := readKey(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key %s: expected %d bytes, got %d", path, ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+func readKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimSpace(string(data))
+	if key, err := hex.DecodeString(text); err == nil {
+		return key, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("key %s is not hex or base64", path)
+	}
+	return key, nil
+}
+
+// lastChain reads the chain of the last line in path, a missing or empty file starts from zero
+// and errChainBroken means there is no chain to continue
+func lastChain(path string) ([]byte, error) {
+	zero := make([]byte, sha256.Size)
+	fh, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return zero, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	info, err := fh.Stat()
+	if err != nil || info.Size() == 0 {
+		return zero, err
+	}
+	if keyID, _ := readHeader(bufio.NewReader(fh)); keyID != "" {
+		return lastChainEncrypted(path)
+	}
+	offset := info.Size() - 64*1024
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, info.Size()-offset)
+	if _, err := fh.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+	// NOTE a torn line has no newline yet, so it is the last line either way
+	lines := bytes.Split(bytes.TrimSuffix(tail, []byte("\n")), []byte("\n"))
+	_, chain, _, err := splitChain(lines[len(lines)-1])
+	if err != nil {
+		return nil, fmt.Errorf("cannot continue hash chain of %s: %w: %v", path, errChainBroken, err)
+	}
+	return chain, nil
+}
+
+// lastChainEncrypted has to decrypt the whole file since records cannot be read backwards
+func lastChainEncrypted(path string) ([]byte, error) {
+	r, err := OpenLog(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	last := []byte{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		last = append(last[:0], scanner.Bytes()...)
+	}
+	if err := scanner.Err(); errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("cannot continue hash chain of %s: %w: %v", path, errChainBroken, err)
+	} else if err != nil {
+		return nil, err
+	}
+	if len(last) == 0 {
+		return make([]byte, sha256.Size), nil
+	}
+	_, chain, _, err := splitChain(last)
+	if err != nil {
+		return nil, fmt.Errorf("cannot continue hash chain of %s: %w: %v", path, errChainBroken, err)
+	}
+	return chain, nil
+}
+
+// splitChain separates a chained line into the content that was hashed, its chain and signature
+func splitChain(line []byte) ([]byte, []byte, []byte, error) {
+	m := chainSuffix.FindSubmatchIndex(line)
+	if m == nil {
+		return nil, nil, nil, fmt.Errorf("no chain field")
+	}
+	content := append(append([]byte{}, line[:m[0]]...), '}')
+	chain, err := hex.DecodeString(string(line[m[2]:m[3]]))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var sig []byte
+	if m[4] >= 0 {
+		if sig, err = base64.StdEncoding.DecodeString(string(line[m[4]:m[5]])); err != nil {
+			return nil, nil, nil, fmt.Errorf("bad signature encoding: %w", err)
+		}
+	}
+	return content, chain, sig, nil
+}
+
+// ChainReport is the result of VerifyChain, Line is the first line that failed
+type ChainReport struct {
+	Entries int    `json:"entries"`
+	Signed  int    `json:"signed"`
+	OK      bool   `json:"ok"`
+	Line    int    `json:"line,omitempty"`
+	Problem string `json:"problem,omitempty"`
+}
+
+// VerifyChain checks that every line continues the hash chain of one file and, when pub is
+// given, carries a valid signature; a modified line or a deleted one both break the chain there
+func VerifyChain(r io.Reader, pub ed25519.PublicKey) (*ChainReport, error) {
+	x := &ChainReport{OK: true}
+	prev := make([]byte, sha256.Size)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		x.Entries++
+		content, chain, sig, err := splitChain(line)
+		if err != nil {
+			return x.fail(err.Error()), nil
+		}
+		if expect := chainHash(prev, content); !bytes.Equal(expect, chain) {
+			return x.fail("hash chain broken, this entry was modified or an entry before it is missing"), nil
+		}
+		if pub != nil {
+			if sig == nil {
+				return x.fail("entry is not signed"), nil
+			}
+			if !ed25519.Verify(pub, chain, sig) {
+				return x.fail("signature does not verify"), nil
+			}
+		}
+		if sig != nil {
+			x.Signed++
+		}
+		prev = chain
+	}
+	return x, scanner.Err()
+}
+
+func (x *ChainReport) fail(problem string) *ChainReport {
+	x.OK = false
+	x.Line = x.Entries
+	x.Problem = problem
+	return x
+}
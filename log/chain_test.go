@@ -0,0 +1,224 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeChained appends one line per message to path, signed when key is set
+func writeChained(t *testing.T, path string, key ed25519.PrivateKey, messages ...string) {
+	t.Helper()
+	fh, err := NewRotatingFile(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != nil {
+		err = fh.EnableSigning(key)
+	} else {
+		err = fh.EnableChain()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range messages {
+		if _, err := fh.Write([]byte(`{"message":"` + message + `"}` + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fh.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func verifyChain(t *testing.T, path string, pub ed25519.PublicKey) *ChainReport {
+	t.Helper()
+	r, err := OpenLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	report, err := VerifyChain(r, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+// editLines rewrites the lines of path with fn
+func editLines(t *testing.T, path string, fn func([][]byte) [][]byte) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := fn(bytes.SplitAfter(data, []byte("\n")))
+	if err := os.WriteFile(path, bytes.Join(lines, nil), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// quietDiagnostics keeps the diagnostics a test provokes off stderr
+func quietDiagnostics(t *testing.T) {
+	SetDiagnostics(nil)
+	t.Cleanup(func() { SetDiagnostics(os.Stderr) })
+}
+
+func TestChainRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeChained(t, path, nil, "one", "two", "three")
+	if report := verifyChain(t, path, nil); !report.OK || report.Entries != 3 {
+		t.Fatalf("fresh chain: %+v", report)
+	}
+	// NOTE a reopened file continues the chain of its last line
+	writeChained(t, path, nil, "four", "five")
+	if report := verifyChain(t, path, nil); !report.OK || report.Entries != 5 {
+		t.Fatalf("continued chain: %+v", report)
+	}
+}
+
+func TestChainDetectsTampering(t *testing.T) {
+	cases := map[string]func([][]byte) [][]byte{
+		"modified": func(lines [][]byte) [][]byte {
+			lines[1] = bytes.Replace(lines[1], []byte("two"), []byte("TWO"), 1)
+			return lines
+		},
+		"deleted": func(lines [][]byte) [][]byte {
+			return append(lines[:1:1], lines[2:]...)
+		},
+		"reordered": func(lines [][]byte) [][]byte {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		},
+		"inserted": func(lines [][]byte) [][]byte {
+			return append(lines[:1:1], append([][]byte{lines[0]}, lines[1:]...)...)
+		},
+	}
+	for name, edit := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "a.log")
+			writeChained(t, path, nil, "one", "two", "three", "four")
+			editLines(t, path, edit)
+			report := verifyChain(t, path, nil)
+			if report.OK || report.Line != 2 {
+				t.Fatalf("expected a break at line 2: %+v", report)
+			}
+		})
+	}
+}
+
+func TestChainDetectsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeChained(t, path, nil, "one", "two", "three")
+	editLines(t, path, func(lines [][]byte) [][]byte {
+		lines[2] = lines[2][:len(lines[2])/2]
+		return lines
+	})
+	if report := verifyChain(t, path, nil); report.OK || report.Line != 3 {
+		t.Fatalf("expected a break at line 3: %+v", report)
+	}
+}
+
+func TestChainMovesTornFileAside(t *testing.T) {
+	quietDiagnostics(t)
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeChained(t, path, nil, "one", "two")
+	editLines(t, path, func(lines [][]byte) [][]byte {
+		lines[1] = lines[1][:10]
+		return lines
+	})
+	writeChained(t, path, nil, "three")
+	if report := verifyChain(t, path, nil); !report.OK || report.Entries != 1 {
+		t.Fatalf("new chain: %+v", report)
+	}
+	aside, _ := filepath.Glob(path + ".broken-*")
+	if len(aside) != 1 {
+		t.Fatalf("expected the torn file moved aside, found %v", aside)
+	}
+	if report := verifyChain(t, aside[0], nil); report.OK || report.Line != 2 {
+		t.Fatalf("moved file should verify up to the torn line: %+v", report)
+	}
+}
+
+func TestChainMovesPlainFileAside(t *testing.T) {
+	quietDiagnostics(t)
+	path := filepath.Join(t.TempDir(), "a.log")
+	if err := os.WriteFile(path, []byte(`{"message":"plain"}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	writeChained(t, path, nil, "one")
+	if report := verifyChain(t, path, nil); !report.OK || report.Entries != 1 {
+		t.Fatalf("new chain: %+v", report)
+	}
+	if aside, _ := filepath.Glob(path + ".broken-*"); len(aside) != 1 {
+		t.Fatalf("expected the plain file moved aside, found %v", aside)
+	}
+}
+
+func TestSignatureRoundTrip(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeChained(t, path, key, "one", "two", "three")
+	if report := verifyChain(t, path, pub); !report.OK || report.Signed != 3 {
+		t.Fatalf("signed chain: %+v", report)
+	}
+
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	if report := verifyChain(t, path, other); report.OK || report.Line != 1 || !strings.Contains(report.Problem, "signature") {
+		t.Fatalf("expected the signature of another key to fail: %+v", report)
+	}
+}
+
+func TestSignatureDetectsUnsignedEntries(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeChained(t, path, nil, "one")
+	if report := verifyChain(t, path, pub); report.OK || report.Problem != "entry is not signed" {
+		t.Fatalf("expected an unsigned entry to fail: %+v", report)
+	}
+}
+
+func TestSignatureDetectsRechainedTampering(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeChained(t, path, key, "one", "two")
+	// NOTE the chain alone can be recomputed by whoever edits the file, the signatures cannot
+	editLines(t, path, func(lines [][]byte) [][]byte {
+		prev := make([]byte, 32)
+		for i, line := range lines {
+			if len(line) == 0 {
+				continue
+			}
+			content, _, sig, err := splitChain(bytes.TrimSuffix(line, []byte("\n")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			content = bytes.Replace(content, []byte("two"), []byte("TWO"), 1)
+			out, chain := appendChain(append(content, '\n'), prev, nil)
+			lines[i] = append(out[:len(out)-2:len(out)-2], []byte(`,"sig":"`+base64.StdEncoding.EncodeToString(sig)+`"}`+"\n")...)
+			prev = chain
+		}
+		return lines
+	})
+	if report := verifyChain(t, path, nil); !report.OK {
+		t.Fatalf("the recomputed chain itself should hold: %+v", report)
+	}
+	if report := verifyChain(t, path, pub); report.OK || report.Line != 2 {
+		t.Fatalf("expected the signature of the edited line to fail: %+v", report)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "fmt"
+
+// ComponentConfig overrides the global config for events carrying Str("component", name)
+type ComponentConfig struct {
+	Level    string          `json:"level" yaml:"level" toml:"level"`
+	Sampling *SamplingConfig `json:"sampling" yaml:"sampling" toml:"sampling"`
+	Sinks    []SinkConfig    `json:"sinks" yaml:"sinks" toml:"sinks"`
+}
+
+type component struct {
+	level   int
+	sample  *sampler
+	sinks   []*sink
+	entries levelCounts // see Metrics.Components
+}
+
+func buildComponents(configs map[string]ComponentConfig, rotation RotationConfig, dryRun bool, files map[string]*RotatingFile) (map[string]*component, error) {
+	components := map[string]*component{}
+	for name, cfg := range configs {
+		x := &component{level: -1}
+		if cfg.Level != "" {
+			mask, ok := parseLevel(cfg.Level)
+			if !ok {
+				return nil, fmt.Errorf("component %s: unknown log level %q", name, cfg.Level)
+			}
+			x.level = mask
+		}
+		if cfg.Sampling != nil {
+			sample, err := newSampler(*cfg.Sampling)
+			if err != nil {
+				return nil, fmt.Errorf("component %s: %w", name, err)
+			}
+			x.sample = sample
+		}
+		if len(cfg.Sinks) > 0 {
+			sinks, err := openSinks(cfg.Sinks, rotation, dryRun, files)
+			if err != nil {
+				return nil, fmt.Errorf("component %s: %w", name, err)
+			}
+			x.sinks = sinks
+		}
+		components[name] = x
+	}
+	return components, nil
+}
+
+func (x *component) sampler(st *state) *sampler {
+	if x == nil || x.sample == nil {
+		return st.sample
+	}
+	return x.sample
+}
+
+// componentConfigs folds the Levels shorthand into Components, a level set in both must agree
+func (x Config) componentConfigs() map[string]ComponentConfig {
+	if len(x.Levels) == 0 {
+		return x.Components
+	}
+	configs := map[string]ComponentConfig{}
+	for name, item := range x.Components {
+		configs[name] = item
+	}
+	for name, level := range x.Levels {
+		item := configs[name]
+		if item.Level == "" {
+			item.Level = level
+		}
+		configs[name] = item
+	}
+	return configs
+}
+
+// Component returns a child of the default logger for one component, see Logger.Component
+func Component(name string) *Logger {
+	return std.Component(name)
+}
+
+// Component returns a child logger whose events carry Str("component", name) and whose SetLevel
+// changes that component only, the rest keeps the logger level:
+//
+//	log.Component("dns").SetLevel(log.DebugLevel)
+func (x *Logger) Component(name string) *Logger {
+	return x.With().Str("component", name).Logger()
+}
+
+// componentLevels are the masks set by SetLevel on component loggers, replaced as a whole
+type componentLevels struct {
+	masks map[string]int
+	floor int
+}
+
+func (x *Logger) setComponentLevel(name string, mask int) {
+	for {
+		old := x.overrides.Load()
+		next := &componentLevels{masks: map[string]int{name: mask}, floor: mask}
+		if old != nil {
+			for key, value := range old.masks {
+				if key != name {
+					next.masks[key] = value
+					next.floor |= value
+				}
+			}
+		}
+		if x.overrides.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+// componentMask is the level set by SetLevel, then the configured one, then the logger level
+func (x *Logger) componentMask(st *state, name string) int {
+	if levels := x.overrides.Load(); levels != nil {
+		if mask, ok := levels.masks[name]; ok {
+			return mask
+		}
+	}
+	if item := st.components[name]; item != nil && item.level != -1 {
+		return item.level
+	}
+	return x.mask()
+}
+
+func (x *Logger) enabled(st *state, name string, level int) bool {
+	return x.componentMask(st, name)&level == level
+}
+
+// floor adds the component levels set by SetLevel to the configured ones
+func (x *Logger) floor(st *state) int {
+	if levels := x.overrides.Load(); levels != nil {
+		return st.floor | levels.floor
+	}
+	return st.floor
+}
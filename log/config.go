@@ -0,0 +1,368 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Config is the declarative logging configuration read by InitFromFile
+type Config struct {
+	Profile  string         `json:"profile" yaml:"profile" toml:"profile"`
+	Level    string         `json:"level" yaml:"level" toml:"level"`
+	Format   string         `json:"format" yaml:"format" toml:"format"`
+	Caller   bool           `json:"caller" yaml:"caller" toml:"caller"`
+	Sequence bool           `json:"sequence" yaml:"sequence" toml:"sequence"`
+	DryRun   bool           `json:"dry_run" yaml:"dry_run" toml:"dry_run"`
+	Sinks    []SinkConfig   `json:"sinks" yaml:"sinks" toml:"sinks"`
+	Rotation RotationConfig `json:"rotation" yaml:"rotation" toml:"rotation"`
+	Redact   RedactConfig   `json:"redact" yaml:"redact" toml:"redact"`
+	Sampling SamplingConfig `json:"sampling" yaml:"sampling" toml:"sampling"`
+	Audit    AuditConfig    `json:"audit" yaml:"audit" toml:"audit"`
+	Async    AsyncConfig    `json:"async" yaml:"async" toml:"async"`
+	Dedup    DedupConfig    `json:"dedup" yaml:"dedup" toml:"dedup"`
+	// StaticFields are written on every entry after the level, see StandardFields
+	StaticFields map[string]string `json:"static_fields" yaml:"static_fields" toml:"static_fields"`
+	// EncryptFields maps field keys to the key ID their values are encrypted with
+	EncryptFields map[string]string `json:"encrypt_fields" yaml:"encrypt_fields" toml:"encrypt_fields"`
+	Retention     RetentionConfig   `json:"retention" yaml:"retention" toml:"retention"`
+	// Timestamp is rfc3339, rfc3339nano, unix, unixms, unixnano or a time.Format layout, UTC
+	// writes it in UTC instead of local time; TimeKey, LevelKey and MessageKey rename those fields
+	Timestamp  string `json:"timestamp" yaml:"timestamp" toml:"timestamp"`
+	UTC        bool   `json:"utc" yaml:"utc" toml:"utc"`
+	TimeKey    string `json:"time_key" yaml:"time_key" toml:"time_key"`
+	LevelKey   string `json:"level_key" yaml:"level_key" toml:"level_key"`
+	MessageKey string `json:"message_key" yaml:"message_key" toml:"message_key"`
+	// DurationUnit is ns, us, ms or s for Dur fields, TimeLayout a time.Format layout for Time fields
+	DurationUnit string `json:"duration_unit" yaml:"duration_unit" toml:"duration_unit"`
+	TimeLayout   string `json:"time_layout" yaml:"time_layout" toml:"time_layout"`
+	// ErrorStackTraces makes Err write the cause chain and a stack of up to ErrorStackDepth frames
+	ErrorStackTraces bool `json:"error_stack_traces" yaml:"error_stack_traces" toml:"error_stack_traces"`
+	ErrorStackDepth  int  `json:"error_stack_depth" yaml:"error_stack_depth" toml:"error_stack_depth"`
+	// CallerSkip adds frames for code that wraps the logger, CallerTrim is a path prefix cut from
+	// the caller instead of keeping only its last directory, CallerFunc adds the function name
+	CallerSkip int    `json:"caller_skip" yaml:"caller_skip" toml:"caller_skip"`
+	CallerTrim string `json:"caller_trim" yaml:"caller_trim" toml:"caller_trim"`
+	CallerFunc bool   `json:"caller_func" yaml:"caller_func" toml:"caller_func"`
+
+	Components map[string]ComponentConfig `json:"components" yaml:"components" toml:"components"`
+	// Levels is a shorthand for the level of components, e.g. {"dns": "debug", "http": "warn"}
+	Levels map[string]string `json:"levels" yaml:"levels" toml:"levels"`
+}
+
+// SinkConfig describes one destination: "stderr", "stdout", "file", "syslog", "journald",
+// "net", whose Path is a URL such as tcp://collector:5170, tls://, udp:// or unix:///run/log.sock,
+// "loki", "elastic", "webhook" and "otlp", whose Path is the Loki, Elasticsearch / OpenSearch,
+// webhook or OTel collector URL
+type SinkConfig struct {
+	Type  string `json:"type" yaml:"type" toml:"type"`
+	Path  string `json:"path" yaml:"path" toml:"path"`
+	Level string `json:"level" yaml:"level" toml:"level"`
+	Index bool   `json:"index" yaml:"index" toml:"index"`
+	Chain bool   `json:"chain" yaml:"chain" toml:"chain"`
+	// SigningKey is the path of an ed25519 private key, signing implies Chain
+	SigningKey string `json:"signing_key" yaml:"signing_key" toml:"signing_key"`
+	// Encrypt is a key ID resolved by the KeyProvider, the file is then only readable through OpenLog
+	Encrypt string `json:"encrypt" yaml:"encrypt" toml:"encrypt"`
+	// WORM never deletes or modifies a written file, rotated files are recorded for VerifyWORM
+	WORM bool `json:"worm" yaml:"worm" toml:"worm"`
+	// HMAC is a key ID resolved by the KeyProvider, every rotated file gets an HMAC file next to it
+	HMAC string `json:"hmac" yaml:"hmac" toml:"hmac"`
+	// Pretty indents entries for reading on a terminal, files always get one entry per line
+	Pretty bool `json:"pretty" yaml:"pretty" toml:"pretty"`
+	// Syslog sets the facility, message format and tag of a "syslog" sink
+	Syslog SyslogConfig `json:"syslog" yaml:"syslog" toml:"syslog"`
+	// Loki sets the stream labels, batching and tenant of a "loki" sink
+	Loki LokiConfig `json:"loki" yaml:"loki" toml:"loki"`
+	// Elastic sets the index template, batching and dead letter file of an "elastic" sink
+	Elastic ElasticConfig `json:"elastic" yaml:"elastic" toml:"elastic"`
+	// Webhook sets the body template, filter, auth and rate limit of a "webhook" sink
+	Webhook WebhookConfig `json:"webhook" yaml:"webhook" toml:"webhook"`
+	// OTLP sets the protocol, headers, resource and batching of an "otlp" sink
+	OTLP OTLPConfig `json:"otlp" yaml:"otlp" toml:"otlp"`
+	// Format is json, logfmt, console, cbor, msgpack or one added with RegisterEncoder, empty
+	// follows Config.Format on stderr and stdout and is json elsewhere
+	Format string `json:"format" yaml:"format" toml:"format"`
+}
+
+// RotationConfig applies to every file sink, zero values disable rotation; Interval is a
+// duration like "24h" and MaxAgeDays removes older backups whatever MaxBackups allows
+type RotationConfig struct {
+	MaxSizeMB  int    `json:"max_size_mb" yaml:"max_size_mb" toml:"max_size_mb"`
+	MaxBackups int    `json:"max_backups" yaml:"max_backups" toml:"max_backups"`
+	MaxAgeDays int    `json:"max_age_days" yaml:"max_age_days" toml:"max_age_days"`
+	Interval   string `json:"interval" yaml:"interval" toml:"interval"`
+	Compress   bool   `json:"compress" yaml:"compress" toml:"compress"`
+}
+
+// RedactConfig masks the values of sensitive keys and any text matching a pattern
+type RedactConfig struct {
+	Keys     []string `json:"keys" yaml:"keys" toml:"keys"`
+	Patterns []string `json:"patterns" yaml:"patterns" toml:"patterns"`
+	Mask     string   `json:"mask" yaml:"mask" toml:"mask"`
+}
+
+// ConfigDecoder has the signature of json.Unmarshal, yaml.Unmarshal and toml.Unmarshal
+type ConfigDecoder func([]byte, interface{}) error
+
+// NOTE only JSON is built in, importing package yaml or toml registers theirs so package log
+// stays without the parsers
+var configDecoders = map[string]ConfigDecoder{
+	".json": json.Unmarshal,
+}
+
+// RegisterConfigDecoder adds a decoder for config files with the given extension
+func RegisterConfigDecoder(ext string, fn ConfigDecoder) {
+	configDecoders[strings.ToLower(ext)] = fn
+}
+
+func LoadConfig(path string) (Config, error) {
+	cfg := Config{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	decode, ok := configDecoders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return cfg, fmt.Errorf("no config decoder registered for %s", path)
+	}
+	if err := decode(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// InitFromFile loads the config file, lets SLOAN_* environment variables override it and applies it
+func InitFromFile(path string) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if cfg, err = ConfigFromEnv(cfg); err != nil {
+		return err
+	}
+	return Init(cfg)
+}
+
+// state is everything derived from a Config, swapped as one pointer so a reload never tears
+type state struct {
+	source Config
+	config Config
+	sinks  []*sink
+	redact *redactor
+	sample *sampler
+	audit  *sink
+	dedup  *deduper
+	static []byte // Config.StaticFields encoded once, each field starting with ','
+	// otherEntries counts entries of components not in the config, see COMPONENT_OTHER
+	otherEntries *levelCounts
+	// inflight counts the events started on this state and not yet written, see retire
+	inflight *atomic.Int64
+
+	fieldCiphers map[string]*fieldCipher
+
+	components map[string]*component
+	// floor has the bits of every component level, events they enable pass the logger level
+	floor int
+}
+
+var empty = &state{}
+
+// active is the state of the default logger
+func active() *state {
+	return std.active()
+}
+
+func (x *state) close() {
+	x.closeExcept(nil)
+}
+
+func (x *state) closeExcept(keep map[io.Writer]bool) {
+	if x == nil {
+		return
+	}
+	x.dedup.flush()
+	closeSinksExcept(x.sinks, keep)
+	if x.audit != nil {
+		closeSinksExcept([]*sink{x.audit}, keep)
+	}
+	for _, item := range x.components {
+		closeSinksExcept(item.sinks, keep)
+	}
+}
+
+// NOTE a replaced config is closed once the events that captured it are written, an event that
+// is started and never sent holds it for STATE_GRACE at most
+const STATE_GRACE = time.Second
+
+// retire closes a state next replaced, leaving open the files next took over
+func (x *state) retire(next *state) {
+	if x == nil {
+		return
+	}
+	deadline := time.Now().Add(STATE_GRACE)
+	for x.inflight != nil && x.inflight.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	// NOTE the counts of the old samplers are written with the new config
+	x.summarize()
+	keep := map[io.Writer]bool{}
+	for _, item := range next.all() {
+		keep[item.w] = true
+	}
+	x.closeExcept(keep)
+}
+
+// reusable returns the files of the file sinks a new config may take over, by fileKey
+func (x *state) reusable() map[string]*RotatingFile {
+	files := map[string]*RotatingFile{}
+	if x == nil {
+		return files
+	}
+	for _, item := range x.all() {
+		if fh, ok := item.w.(*RotatingFile); ok && item.key != "" {
+			files[item.key] = fh
+		}
+	}
+	return files
+}
+
+// build opens what cfg describes, taking over the unchanged files of prev, the running state
+func build(cfg Config, prev *state) (*state, int, error) {
+	level := LOG_ERROR
+	if cfg.Level != "" {
+		mask, ok := parseLevel(cfg.Level)
+		if !ok {
+			return nil, 0, fmt.Errorf("unknown log level %q", cfg.Level)
+		}
+		level = mask
+	}
+
+	redact, err := newRedactor(cfg.Redact)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sample, err := newSampler(cfg.Sampling)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fieldCiphers, err := buildFieldCiphers(cfg.EncryptFields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dedup, err := newDeduper(cfg.Dedup)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	files := prev.reusable()
+	sinks, err := openSinks(cfg.Sinks, cfg.Rotation, cfg.DryRun, files)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	x := &state{config: cfg, sinks: sinks, redact: redact, sample: sample, fieldCiphers: fieldCiphers, dedup: dedup, otherEntries: &levelCounts{}, inflight: &atomic.Int64{}}
+	x.static = x.encodeStatic(cfg.StaticFields)
+	if x.components, err = buildComponents(cfg.componentConfigs(), cfg.Rotation, cfg.DryRun, files); err != nil {
+		closeSinks(sinks)
+		return nil, 0, err
+	}
+	for _, item := range x.components {
+		if item.level != -1 {
+			x.floor |= item.level
+		}
+	}
+	if cfg.Audit.Path != "" {
+		if x.audit, err = openSink(SinkConfig{Type: "file", Path: cfg.Audit.Path, Chain: cfg.Audit.Chain, SigningKey: cfg.Audit.SigningKey, Encrypt: cfg.Audit.Encrypt, WORM: cfg.Audit.WORM, HMAC: cfg.Audit.HMAC}, cfg.Rotation, cfg.DryRun, files); err != nil {
+			x.close()
+			return nil, 0, err
+		}
+	}
+	if cfg.Format == "console" {
+		for _, item := range x.all() {
+			if (item.name == "stderr" || item.name == "stdout") && item.format == "" && item.encoder == nil {
+				item.setFormat("console", false)
+			}
+		}
+	}
+	for _, item := range x.all() {
+		if console, ok := item.encoder.(*ConsoleWriter); ok {
+			console.entries = cfg
+		}
+		if journal, ok := item.w.(*JournaldWriter); ok {
+			journal.entries = cfg
+		}
+	}
+	if cfg.Async.Enabled {
+		for _, item := range x.all() {
+			if item != x.audit {
+				item.queue = newAsyncQueue(item, cfg.Async)
+			}
+		}
+	}
+	return x, level, nil
+}
+
+// apply configures the default logger
+func apply(source Config) error {
+	return std.Apply(source)
+}
+
+// Apply validates and opens everything first, then swaps, so a bad config leaves the old one
+// running; the default logger also stops writing to the InitLogger destinations
+func (x *Logger) Apply(source Config) error {
+	cfg := remoteOverride().merge(source.withProfile()).withDefaults()
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	next, level, err := build(cfg, x.current.Load())
+	if err != nil {
+		return err
+	}
+	next.source = source
+	for _, item := range next.all() {
+		item.borrowed = false
+	}
+
+	old := x.current.Swap(next)
+	defer old.retire(next)
+	x.level.Store(int32(level))
+	x.overrides.Store(nil)
+	if !x.legacy {
+		return nil
+	}
+	LOG_LEVEL = level
+	legacyMu.Lock()
+	defer legacyMu.Unlock()
+	LOG_STDERR = false
+	if LOG_FH != nil {
+		LOG_FH.Close()
+		LOG_FH = nil
+	}
+	LOG_FILE = ""
+	for _, item := range cfg.Sinks {
+		if item.Type == "file" {
+			LOG_FILE = item.Path
+			break
+		}
+	}
+	return nil
+}
+
+// Init replaces the stderr/LOG_FH destinations set up by InitLogger with the configured sinks
+func Init(cfg Config) error {
+	if err := apply(cfg); err != nil {
+		return err
+	}
+	Startup()
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NOTE ANSI colors of the console level labels, keys are dimmed and errors red
+var CONSOLE_LEVELS = map[string][2]string{
+	"trace": {"TRC", "90"},
+	"debug": {"DBG", "36"},
+	"info":  {"INF", "32"},
+	"warn":  {"WRN", "33"},
+	"error": {"ERR", "31"},
+	"fatal": {"FTL", "1;31"},
+	"panic": {"PNC", "1;31"},
+	"audit": {"AUD", "34"},
+}
+
+// ConsoleWriter renders entries as "15:04:05 INF message key=value" for reading on a terminal,
+// lines that are not JSON are written as they are
+type ConsoleWriter struct {
+	Out        io.Writer
+	NoColor    bool
+	TimeFormat string // a time.Format layout, "15:04:05" when empty
+
+	entries Config // the keys and timestamp the entries are written with
+}
+
+// NewConsoleWriter colors only when out is a terminal and NO_COLOR is not set
+func NewConsoleWriter(out io.Writer) *ConsoleWriter {
+	return &ConsoleWriter{Out: out, NoColor: !isTerminal(out) || os.Getenv("NO_COLOR") != ""}
+}
+
+func isTerminal(w io.Writer) bool {
+	fh, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := fh.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Write renders one entry per call, which is how every sink is written
+func (x *ConsoleWriter) Write(p []byte) (int, error) {
+	if _, err := x.Out.Write(x.Encode(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Encode makes the ConsoleWriter the Encoder of console sinks
+func (x *ConsoleWriter) Encode(line []byte) []byte {
+	keys, values, ok := orderedFields(line)
+	if !ok {
+		return line
+	}
+	timeKey, levelKey, messageKey := x.entries.timeKey(), x.entries.levelKey(), x.entries.messageKey()
+	text := func(key string) string {
+		value := ""
+		json.Unmarshal(values[key], &value)
+		return value
+	}
+
+	layout := x.TimeFormat
+	if layout == "" {
+		layout = "15:04:05"
+	}
+	stamp := string(values[timeKey])
+	if t := parseTimestamp(decodeValue(values[timeKey]), x.entries); !t.IsZero() {
+		stamp = t.Local().Format(layout)
+	}
+	label, color := strings.ToUpper(text(levelKey)), ""
+	if item, ok := CONSOLE_LEVELS[text(levelKey)]; ok {
+		label, color = item[0], item[1]
+	}
+
+	buf := append([]byte(stamp), ' ')
+	buf = append(x.paint(buf, color, label), ' ')
+	buf = append(buf, text(messageKey)...)
+	for _, key := range keys {
+		if key == timeKey || key == levelKey || key == messageKey {
+			continue
+		}
+		color := ""
+		if key == "error" {
+			color = "31"
+		}
+		buf = x.paint(x.paint(append(buf, ' '), "90", key+"="), color, string(consoleValue(values[key])))
+	}
+	return append(buf, '\n')
+}
+
+func (x *ConsoleWriter) paint(buf []byte, color, text string) []byte {
+	if x.NoColor || color == "" {
+		return append(buf, text...)
+	}
+	return append(append(append(append(append(buf, "\x1b["...), color...), 'm'), text...), "\x1b[0m"...)
+}
+
+// orderedFields decodes the top level of an entry keeping the order its fields were written in
+func orderedFields(line []byte) ([]string, map[string]json.RawMessage, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, nil, false
+	}
+	keys, values := []string{}, map[string]json.RawMessage{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, false
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, false
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+	return keys, values, true
+}
+
+// consoleValue writes strings bare unless they need quotes, everything else as JSON
+func consoleValue(value json.RawMessage) []byte {
+	text := ""
+	if json.Unmarshal(value, &text) != nil {
+		return value
+	}
+	if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+		return []byte(strconv.Quote(text))
+	}
+	return []byte(text)
+}
@@ -0,0 +1,104 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "time"
+
+// Context collects the fields of a child logger, see With
+type Context struct {
+	e *Event
+}
+
+// With starts a child of the default logger
+func With() *Context {
+	return std.With()
+}
+
+// With starts a child logger whose fields are added to every event it writes:
+//
+//	scanner := log.With().Str("component", "scanner").Str("job_id", id).Logger()
+//
+// values are redacted and encrypted with the config in effect now
+func (x *Logger) With() *Context {
+	return &Context{e: &Event{logger: x, st: x.active(), component: x.component}}
+}
+
+func (x *Context) Str(key, value string) *Context {
+	x.e.Str(key, value)
+	return x
+}
+
+func (x *Context) Int(key string, value int) *Context {
+	x.e.Int(key, value)
+	return x
+}
+
+func (x *Context) Int64(key string, value int64) *Context {
+	x.e.Int64(key, value)
+	return x
+}
+
+func (x *Context) Float(key string, value float32) *Context {
+	x.e.Float(key, value)
+	return x
+}
+
+func (x *Context) Uint64(key string, value uint64) *Context {
+	x.e.Uint64(key, value)
+	return x
+}
+
+func (x *Context) Float64(key string, value float64) *Context {
+	x.e.Float64(key, value)
+	return x
+}
+
+func (x *Context) Bool(key string, value bool) *Context {
+	x.e.Bool(key, value)
+	return x
+}
+
+func (x *Context) Dur(key string, d time.Duration) *Context {
+	x.e.Dur(key, d)
+	return x
+}
+
+func (x *Context) Time(key string, t time.Time) *Context {
+	x.e.Time(key, t)
+	return x
+}
+
+func (x *Context) Strs(key string, values []string) *Context {
+	x.e.Strs(key, values)
+	return x
+}
+
+func (x *Context) Dict(key string, d ILogger) *Context {
+	x.e.Dict(key, d)
+	return x
+}
+
+func (x *Context) Any(key string, v interface{}) *Context {
+	x.e.Any(key, v)
+	return x
+}
+
+func (x *Context) Err(err error) *Context {
+	x.e.Err(err)
+	return x
+}
+
+// Logger returns the child, it shares sinks and config with its parent
+func (x *Context) Logger() *Logger {
+	parent := x.e.logger
+	return &Logger{
+		current:   parent.current,
+		level:     parent.level,
+		overrides: parent.overrides,
+		sample:    parent.sample,
+		writers:   parent.writers,
+		hooks:     parent.hooks,
+		legacy:    parent.legacy,
+		fields:    append(append([]byte{}, parent.fields...), x.e.buf...),
+		component: x.e.component,
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// REQUEST_ID_HEADER carries the request ID between services, read by Middleware and set by
+// Transport
+const REQUEST_ID_HEADER = "X-Request-ID"
+
+// NOTE an incoming ID longer than REQUEST_ID_MAX or with characters outside printable ASCII is
+// replaced rather than trusted, it ends up in every entry of the request
+const REQUEST_ID_MAX = 128
+
+// NEW_REQUEST_ID makes the IDs of requests that arrive without one, UUIDv7 sorts by time; set
+// it to NewXID for shorter IDs
+var NEW_REQUEST_ID = NewUUIDv7
+
+// NewUUIDv7 returns an RFC 9562 version 7 UUID, a millisecond timestamp followed by random bits
+func NewUUIDv7() string {
+	var id [16]byte
+	rand.Read(id[6:])
+	ms := uint64(time.Now().UnixMilli())
+	id[0], id[1], id[2], id[3], id[4], id[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	id[6] = id[6]&0x0f | 0x70
+	id[8] = id[8]&0x3f | 0x80
+	text := make([]byte, 36)
+	hex.Encode(text, id[:4])
+	text[8] = '-'
+	hex.Encode(text[9:], id[4:6])
+	text[13] = '-'
+	hex.Encode(text[14:], id[6:8])
+	text[18] = '-'
+	hex.Encode(text[19:], id[8:10])
+	text[23] = '-'
+	hex.Encode(text[24:], id[10:])
+	return string(text)
+}
+
+var xidEncoding = base32.NewEncoding("0123456789abcdefghijklmnopqrstuv").WithPadding(base32.NoPadding)
+var xidMachine [5]byte
+var xidCounter atomic.Uint32
+
+func init() {
+	var seed [7]byte
+	rand.Read(seed[:])
+	xidMachine = [5]byte{seed[0], seed[1], seed[2], byte(os.Getpid() >> 8), byte(os.Getpid())}
+	xidCounter.Store(uint32(seed[4])<<16 | uint32(seed[5])<<8 | uint32(seed[6]))
+}
+
+// NewXID returns a 20 character xid, seconds, a per process random part, the pid and a counter
+func NewXID() string {
+	var id [12]byte
+	binary.BigEndian.PutUint32(id[:4], uint32(time.Now().Unix()))
+	copy(id[4:9], xidMachine[:])
+	count := xidCounter.Add(1)
+	id[9], id[10], id[11] = byte(count>>16), byte(count>>8), byte(count)
+	return xidEncoding.EncodeToString(id[:])
+}
+
+// EnsureRequestID returns ctx with a request ID, the one it has or a new one, for work that
+// starts without a request such as a scheduled scan
+func EnsureRequestID(ctx context.Context) (context.Context, string) {
+	if id := RequestID(ctx); id != "" {
+		return ctx, id
+	}
+	id := NEW_REQUEST_ID()
+	return WithRequestID(ctx, id), id
+}
+
+// ValidRequestID tells whether an incoming ID may be used as is
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > REQUEST_ID_MAX {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Transport sets the request ID of the request context as REQUEST_ID_HEADER on outgoing calls so
+// the next service logs the same ID, a nil next is http.DefaultTransport:
+//
+//	client := &http.Client{Transport: log.Transport(nil)}
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper(func(r *http.Request) (*http.Response, error) {
+		id := RequestID(r.Context())
+		if id == "" || r.Header.Get(REQUEST_ID_HEADER) != "" {
+			return next.RoundTrip(r)
+		}
+		// NOTE a RoundTripper must not modify the request it is given
+		r = r.Clone(r.Context())
+		r.Header.Set(REQUEST_ID_HEADER, id)
+		return next.RoundTrip(r)
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (x roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return x(r)
+}
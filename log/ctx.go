@@ -0,0 +1,94 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+type loggerKey struct{}
+type requestIDKey struct{}
+type traceIDKey struct{}
+
+// ContextField reads one field from a context, e.g. a trace ID set by a tracing library
+type ContextField func(context.Context) (string, bool)
+
+type contextField struct {
+	key string
+	fn  ContextField
+}
+
+var contextFieldsMu sync.RWMutex
+var contextFields = []contextField{
+	{"request_id", stringValue(requestIDKey{})},
+	{"trace_id", stringValue(traceIDKey{})},
+}
+
+// RegisterContextField adds a field Ctx copies from every context that has it, after the fields
+// of the same key registered before
+func RegisterContextField(key string, fn ContextField) {
+	contextFieldsMu.Lock()
+	defer contextFieldsMu.Unlock()
+	contextFields = append(contextFields, contextField{key, fn})
+}
+
+func stringValue(key interface{}) ContextField {
+	return func(ctx context.Context) (string, bool) {
+		value, ok := ctx.Value(key).(string)
+		return value, ok && value != ""
+	}
+}
+
+// WithRequestID stores the request ID Ctx writes as "request_id"
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored with WithRequestID, e.g. to pass it on to a service called
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithTraceID stores the trace ID Ctx writes as "trace_id"
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// WithContext returns a copy of ctx carrying the logger for Ctx
+func (x *Logger) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loggerKey{}, x)
+}
+
+// Ctx returns the logger stored in ctx, or the default logger, with the request ID, trace ID
+// and registered context fields of ctx attached
+func Ctx(ctx context.Context) *Logger {
+	x, ok := ctx.Value(loggerKey{}).(*Logger)
+	if !ok {
+		x = std
+	}
+	contextFieldsMu.RLock()
+	defer contextFieldsMu.RUnlock()
+	// NOTE the first field of a key that ctx has wins, so WithTraceID overrides a registered
+	// source of trace_id such as package otel
+	var child *Context
+	seen := map[string]bool{}
+	for _, field := range contextFields {
+		if seen[field.key] {
+			continue
+		}
+		value, ok := field.fn(ctx)
+		if !ok {
+			continue
+		}
+		if child == nil {
+			child = x.With()
+		}
+		child.Str(field.key, value)
+		seen[field.key] = true
+	}
+	if child == nil {
+		return x
+	}
+	return child.Logger()
+}
@@ -0,0 +1,91 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var ErrMalformedEntry = errors.New("malformed log entry")
+
+// Entry is one decoded log line, Fields holds everything except time, level and message
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Fields  map[string]interface{}
+	Raw     []byte
+}
+
+// Field returns a field as text, JSON encoded when it is not a string
+func (x *Entry) Field(key string) (string, bool) {
+	value, ok := x.Fields[key]
+	if !ok {
+		return "", false
+	}
+	if text, ok := value.(string); ok {
+		return text, true
+	}
+	data, _ := json.Marshal(value)
+	return string(data), true
+}
+
+// ParseEntry decodes a single NDJSON line, numbers are kept as json.Number
+func ParseEntry(line []byte) (*Entry, error) {
+	x := &Entry{Raw: line, Fields: map[string]interface{}{}}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&x.Fields); err != nil {
+		return x, fmt.Errorf("%w: %s", ErrMalformedEntry, err)
+	}
+
+	// NOTE entries written with the keys and timestamp of the default logger config are read
+	// the same way, otherwise the standard keys are used
+	cfg := active().config
+	timeKey, levelKey, messageKey := cfg.timeKey(), cfg.levelKey(), cfg.messageKey()
+	if _, ok := x.Fields[timeKey]; !ok {
+		timeKey = "time"
+	}
+	if _, ok := x.Fields[levelKey]; !ok {
+		levelKey = "level"
+	}
+	if _, ok := x.Fields[messageKey]; !ok {
+		messageKey = "message"
+	}
+	x.Time = parseTimestamp(x.Fields[timeKey], cfg)
+	x.Level, _ = x.Fields[levelKey].(string)
+	x.Message, _ = x.Fields[messageKey].(string)
+	delete(x.Fields, timeKey)
+	delete(x.Fields, levelKey)
+	delete(x.Fields, messageKey)
+	return x, nil
+}
+
+// Decoder reads entries from a stream of sloan NDJSON
+type Decoder struct {
+	scanner *bufio.Scanner
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &Decoder{scanner: scanner}
+}
+
+// Decode returns the next entry or io.EOF, a line that is not JSON returns ErrMalformedEntry
+// with Raw set and decoding can continue with the next call
+func (x *Decoder) Decode() (*Entry, error) {
+	if !x.scanner.Scan() {
+		if err := x.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	line := append([]byte{}, x.scanner.Bytes()...)
+	return ParseEntry(line)
+}
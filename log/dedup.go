@@ -0,0 +1,143 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DedupConfig writes the first entry of each level, component, message and the listed Fields
+// right away and drops identical entries for Window after it; when the window closes one copy of
+// the first entry is written with "repeated" set to how many were dropped, none when there were
+// none
+type DedupConfig struct {
+	Window string   `json:"window" yaml:"window" toml:"window"`
+	Fields []string `json:"fields" yaml:"fields" toml:"fields"`
+}
+
+type deduper struct {
+	window  time.Duration
+	fields  []string
+	mu      sync.Mutex
+	pending map[uint64]*duplicate
+	timer   *time.Timer
+}
+
+// duplicate is an entry written in the open window of its kind and where its summary goes
+type duplicate struct {
+	logger    *Logger
+	st        *state
+	comp      *component
+	level     int
+	component string
+	out       []byte
+	repeated  int
+	until     time.Time
+}
+
+func newDeduper(cfg DedupConfig) (*deduper, error) {
+	if cfg.Window == "" {
+		return nil, nil
+	}
+	window, err := time.ParseDuration(cfg.Window)
+	if err != nil {
+		return nil, fmt.Errorf("dedup: %w", err)
+	}
+	return &deduper{window: window, fields: cfg.Fields, pending: map[uint64]*duplicate{}}, nil
+}
+
+// hold counts the entry and keeps it back when its kind was already written in the open window,
+// false means it is written right away; fatal and panic entries are never held
+func (x *deduper) hold(e *Event, comp *component, msg string, out []byte) bool {
+	if x == nil || e.terminate != "" || e.summary {
+		return false
+	}
+	h := fnv.New64a()
+	h.Write(strconv.AppendInt(nil, int64(e.level), 10))
+	for _, part := range []string{e.component, msg} {
+		h.Write([]byte{0})
+		h.Write([]byte(part))
+	}
+	for _, key := range x.fields {
+		h.Write([]byte{0})
+		h.Write(fieldValue(e.buf, key))
+	}
+	key := h.Sum64()
+
+	now := time.Now()
+	x.mu.Lock()
+	item, ok := x.pending[key]
+	if ok && now.Before(item.until) {
+		item.repeated++
+		x.mu.Unlock()
+		return true
+	}
+	x.pending[key] = &duplicate{logger: e.logger, st: e.st, comp: comp, level: e.level, component: e.component, out: out, until: now.Add(x.window)}
+	// NOTE one timer serves every kind, it fires when the oldest open window closes
+	if x.timer == nil {
+		x.timer = time.AfterFunc(x.window, x.sweep)
+	}
+	x.mu.Unlock()
+	// NOTE the window of this kind closed before the sweep got to it
+	if ok {
+		item.write()
+	}
+	return false
+}
+
+// sweep closes the windows that are over, writes their summaries and sets the timer for the
+// next window to close
+func (x *deduper) sweep() {
+	now := time.Now()
+	closed := []*duplicate{}
+	x.mu.Lock()
+	next := time.Time{}
+	for key, item := range x.pending {
+		if !now.Before(item.until) {
+			delete(x.pending, key)
+			closed = append(closed, item)
+		} else if next.IsZero() || item.until.Before(next) {
+			next = item.until
+		}
+	}
+	x.timer = nil
+	if !next.IsZero() {
+		x.timer = time.AfterFunc(next.Sub(now), x.sweep)
+	}
+	x.mu.Unlock()
+	for _, item := range closed {
+		item.write()
+	}
+}
+
+// flush closes every open window now, before a Flush or before the sinks are closed
+func (x *deduper) flush() {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	pending := x.pending
+	x.pending = map[uint64]*duplicate{}
+	if x.timer != nil {
+		x.timer.Stop()
+		x.timer = nil
+	}
+	x.mu.Unlock()
+	for _, item := range pending {
+		item.write()
+	}
+}
+
+// write writes the summary of a closed window, nothing when no entry was dropped
+func (x *duplicate) write() {
+	if x.repeated == 0 {
+		return
+	}
+	out := append(strconv.AppendInt(append(x.out[:len(x.out)-2:len(x.out)-2], ",\"repeated\":"...), int64(x.repeated), 10), '}', '\n')
+	e := &Event{logger: x.logger, st: x.st, level: x.level, component: x.component}
+	countEntry(e, out)
+	e.route(x.st, x.comp, out)
+}
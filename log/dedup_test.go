@@ -0,0 +1,97 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+// capture applies cfg as a dry run and returns the entries written after the startup entry, what
+// is still held is flushed after the test stopped listening
+func capture(t *testing.T, cfg Config) <-chan Entry {
+	t.Helper()
+	cfg.DryRun = true
+	if err := Init(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Flush() })
+	entries, unsubscribe := Subscribe(func(x *Entry) bool { return x.Message != "startup" })
+	t.Cleanup(unsubscribe)
+	return entries
+}
+
+// received waits up to a second for the next entry
+func received(t *testing.T, entries <-chan Entry) Entry {
+	t.Helper()
+	select {
+	case entry := <-entries:
+		return entry
+	case <-time.After(time.Second):
+		t.Fatal("no entry")
+	}
+	return Entry{}
+}
+
+// quiet fails when an entry arrives within wait
+func quiet(t *testing.T, entries <-chan Entry, wait time.Duration) {
+	t.Helper()
+	select {
+	case entry := <-entries:
+		t.Fatalf("unexpected entry: %s", entry.Raw)
+	case <-time.After(wait):
+	}
+}
+
+func TestDedupSummarizesWindow(t *testing.T) {
+	entries := capture(t, Config{Level: "info", Dedup: DedupConfig{Window: "50ms"}})
+	for i := 0; i < 4; i++ {
+		Info().Msg("disk full")
+	}
+	if entry := received(t, entries); entry.Message != "disk full" || entry.Fields["repeated"] != nil {
+		t.Fatalf("first entry: %s", entry.Raw)
+	}
+	quiet(t, entries, 20*time.Millisecond)
+	entry := received(t, entries)
+	if repeated, _ := entry.Field("repeated"); entry.Message != "disk full" || repeated != "3" {
+		t.Fatalf("summary: %s", entry.Raw)
+	}
+}
+
+func TestDedupKeepsDistinctFields(t *testing.T) {
+	entries := capture(t, Config{Level: "info", Dedup: DedupConfig{Window: "1m", Fields: []string{"host"}}})
+	Info().Str("host", "a").Msg("lookup failed")
+	Info().Str("host", "b").Msg("lookup failed")
+	Info().Str("host", "a").Msg("lookup failed")
+	for _, host := range []string{"a", "b"} {
+		entry := received(t, entries)
+		if value, _ := entry.Field("host"); value != host {
+			t.Fatalf("expected host %s, got %s", host, value)
+		}
+	}
+	quiet(t, entries, 20*time.Millisecond)
+}
+
+func TestDedupFlushWritesOpenWindows(t *testing.T) {
+	entries := capture(t, Config{Level: "info", Dedup: DedupConfig{Window: "1m"}})
+	Info().Msg("retrying")
+	Info().Msg("retrying")
+	received(t, entries)
+	if err := Flush(); err != nil {
+		t.Fatal(err)
+	}
+	entry := received(t, entries)
+	if repeated, _ := entry.Field("repeated"); repeated != "1" {
+		t.Fatal("flush did not write the summary")
+	}
+}
+
+func TestDedupKeepsLevelsApart(t *testing.T) {
+	entries := capture(t, Config{Level: "info", Dedup: DedupConfig{Window: "1m"}})
+	Info().Msg("same")
+	Error().Msg("same")
+	for _, level := range []string{"info", "error"} {
+		if entry := received(t, entries); entry.Level != level {
+			t.Fatalf("expected %s, got %s", level, entry.Raw)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// DIAG_KEEP is how many diagnostics Diagnostics returns
+const DIAG_KEEP = 64
+
+// Diagnostic is a problem of the logger itself, Op is what failed and Target the file or sink
+type Diagnostic struct {
+	Time   time.Time `json:"time"`
+	Level  string    `json:"level"`
+	Op     string    `json:"op"`
+	Target string    `json:"target,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// NOTE diagnostics never go through the logger so a failing sink cannot recurse or lose them
+var diagMu sync.Mutex
+var diagOut io.Writer = os.Stderr
+var diagRing []Diagnostic
+
+// SetDiagnostics sends the logger's own problems to w as JSON lines, nil keeps only Diagnostics
+func SetDiagnostics(w io.Writer) {
+	diagMu.Lock()
+	defer diagMu.Unlock()
+	diagOut = w
+}
+
+// Diagnostics returns the most recent problems, oldest first
+func Diagnostics() []Diagnostic {
+	diagMu.Lock()
+	defer diagMu.Unlock()
+	return append([]Diagnostic{}, diagRing...)
+}
+
+func diagnose(op, target string, err error) {
+	x := Diagnostic{Time: time.Now(), Level: "error", Op: op, Target: target}
+	if err != nil {
+		x.Error = err.Error()
+	} else {
+		x.Level = "info"
+	}
+	diagMu.Lock()
+	defer diagMu.Unlock()
+	if len(diagRing) == DIAG_KEEP {
+		diagRing = append(diagRing[:0], diagRing[1:]...)
+	}
+	diagRing = append(diagRing, x)
+	if diagOut != nil {
+		data, _ := json.Marshal(x)
+		diagOut.Write(append(data, '\n'))
+	}
+}
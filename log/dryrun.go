@@ -0,0 +1,51 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "sync"
+
+// DRY_RUN_KEEP is how many of the latest entries DryRunReport shows per sink
+const DRY_RUN_KEEP = 10
+
+// DryRunSink is what one sink would have written since the dry run config was applied
+type DryRunSink struct {
+	Name    string   `json:"name"`
+	Entries int      `json:"entries"`
+	Bytes   int      `json:"bytes"`
+	Latest  []string `json:"latest"`
+}
+
+// dryRunWriter stands in for every sink of a Config with DryRun set, nothing is opened or written
+type dryRunWriter struct {
+	mu      sync.Mutex
+	entries int
+	bytes   int
+	latest  []string
+}
+
+func (x *dryRunWriter) Write(p []byte) (int, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.entries++
+	x.bytes += len(p)
+	if len(x.latest) == DRY_RUN_KEEP {
+		x.latest = append(x.latest[:0], x.latest[1:]...)
+	}
+	x.latest = append(x.latest, string(p[:len(p)-1]))
+	return len(p), nil
+}
+
+// DryRunReport returns what each sink would have written, nil when the config is not a dry run;
+// a config with problems never gets this far since Init reports them without applying it
+func DryRunReport() []DryRunSink {
+	report := []DryRunSink{}
+	for _, item := range active().all() {
+		w, ok := item.w.(*dryRunWriter)
+		if !ok {
+			return nil
+		}
+		w.mu.Lock()
+		report = append(report, DryRunSink{Name: item.name, Entries: w.entries, Bytes: w.bytes, Latest: append([]string{}, w.latest...)})
+		w.mu.Unlock()
+	}
+	return report
+}
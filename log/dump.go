@@ -0,0 +1,130 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// withDefaults spells out every default so the effective config shows exactly what is running
+func (x Config) withDefaults() Config {
+	if x.Level == "" {
+		x.Level = levelName(LOG_ERROR)
+	}
+	if x.Format == "" {
+		x.Format = "json"
+	}
+	if x.DurationUnit == "" {
+		x.DurationUnit = "ms"
+	}
+	if x.Timestamp == "" {
+		x.Timestamp = "rfc3339"
+	}
+	x.TimeKey, x.LevelKey, x.MessageKey = x.timeKey(), x.levelKey(), x.messageKey()
+	if x.TimeLayout == "" {
+		x.TimeLayout = time.RFC3339Nano
+	}
+	if x.ErrorStackTraces && x.ErrorStackDepth == 0 {
+		x.ErrorStackDepth = ERROR_STACK_DEPTH
+	}
+	if len(x.Sinks) == 0 {
+		x.Sinks = []SinkConfig{{Type: "stderr"}}
+	}
+	if x.Redact.Mask == "" && (len(x.Redact.Keys) > 0 || len(x.Redact.Patterns) > 0) {
+		x.Redact.Mask = REDACT_MASK
+	}
+	if x.Async.Enabled && x.Async.Buffer == 0 {
+		x.Async.Buffer = ASYNC_BUFFER
+	}
+	if x.Async.Enabled && x.Async.Policy == "" {
+		x.Async.Policy = "block"
+	}
+	if x.Retention.Days > 0 && x.Retention.Action == "" {
+		x.Retention.Action = "delete"
+	}
+	return x
+}
+
+// EffectiveConfig is the config after the file, SLOAN_* environment, profile, remote override
+// and defaults are merged, or what InitLogger set up when no Config was applied; secrets are
+// masked with REDACT_MASK since the result ends up in logs and admin endpoints, see scrubbed
+func EffectiveConfig() Config {
+	if x := std.current.Load(); x != nil {
+		cfg := x.config
+		cfg.Level = levelName(std.mask())
+		cfg.Sinks = scrubSinks(cfg.Sinks)
+		if cfg.Components != nil {
+			components := make(map[string]ComponentConfig, len(cfg.Components))
+			for name, item := range cfg.Components {
+				item.Sinks = scrubSinks(item.Sinks)
+				components[name] = item
+			}
+			cfg.Components = components
+		}
+		return cfg
+	}
+	cfg := Config{Level: levelName(std.mask()), Format: "json", Sinks: []SinkConfig{}}
+	if LOG_STDERR {
+		cfg.Sinks = append(cfg.Sinks, SinkConfig{Type: "stderr"})
+	}
+	if LOG_FH != nil {
+		cfg.Sinks = append(cfg.Sinks, SinkConfig{Type: "file", Path: LOG_FILE})
+	}
+	return cfg
+}
+
+// DumpConfig returns EffectiveConfig as JSON
+func DumpConfig() ([]byte, error) {
+	return json.Marshal(EffectiveConfig())
+}
+
+func levelName(mask int) string {
+	return levelFromMask(mask).String()
+}
+
+func scrubSinks(sinks []SinkConfig) []SinkConfig {
+	if sinks == nil {
+		return nil
+	}
+	result := make([]SinkConfig, len(sinks))
+	for i, item := range sinks {
+		result[i] = item.scrubbed()
+	}
+	return result
+}
+
+// scrubbed masks the password of a sink URL like the sink names do, the path and query of a webhook URL, which often
+// carry the token (Slack, Discord), and the Auth and OTLP header values that are more than
+// $VAR references
+func (x SinkConfig) scrubbed() SinkConfig {
+	if target, err := url.Parse(x.Path); err == nil && target.Host != "" {
+		x.Path = target.Redacted()
+		if x.Type == "webhook" && (strings.Trim(target.Path, "/") != "" || target.RawQuery != "") {
+			x.Path = target.Scheme + "://" + target.Host + "/" + REDACT_MASK
+		}
+	}
+	x.Webhook.Auth = maskSecret(x.Webhook.Auth)
+	if x.OTLP.Headers != nil {
+		headers := make(map[string]string, len(x.OTLP.Headers))
+		for key, value := range x.OTLP.Headers {
+			headers[key] = maskSecret(value)
+		}
+		x.OTLP.Headers = headers
+	}
+	return x
+}
+
+var envReference = regexp.MustCompile(`\$\{?\w+\}?`)
+
+// maskSecret keeps a value that only names its secret, "${TOKEN}" or "Bearer ${TOKEN}", and
+// masks a literal one
+func maskSecret(value string) string {
+	words := strings.Fields(envReference.ReplaceAllString(value, ""))
+	if len(words) == 0 || len(words) == 1 && envReference.MatchString(value) {
+		return value
+	}
+	return REDACT_MASK
+}
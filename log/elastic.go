@@ -0,0 +1,312 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NOTE an ElasticWriter sends a _bulk request when ELASTIC_BATCH lines are pending or the oldest
+// has waited ELASTIC_WAIT; rejected (429) and unreachable batches are retried ELASTIC_RETRIES
+// times with the NetWriter backoff, then written to the dead letter file like the lines that do
+// not fit in its ELASTIC_BUFFER while the cluster pushes back
+const (
+	ELASTIC_BATCH   = 500
+	ELASTIC_WAIT    = time.Second
+	ELASTIC_BUFFER  = 8192
+	ELASTIC_RETRIES = 5
+	ELASTIC_INDEX   = "logs-{2006.01.02}"
+)
+
+// ElasticConfig applies to sinks of type "elastic", whose Path is the cluster URL, e.g.
+// https://user:pass@es:9200, and works the same against OpenSearch; the {...} part of Index is a
+// time layout of the UTC write time, e.g. "osintami-logs-{2006.01.02}", DeadLetter the file
+// entries go to when the cluster cannot take them
+type ElasticConfig struct {
+	Index      string `json:"index" yaml:"index" toml:"index"`
+	BatchSize  int    `json:"batch_size" yaml:"batch_size" toml:"batch_size"`
+	BatchWait  string `json:"batch_wait" yaml:"batch_wait" toml:"batch_wait"`
+	DeadLetter string `json:"dead_letter" yaml:"dead_letter" toml:"dead_letter"`
+}
+
+type elasticEntry struct {
+	index string
+	line  []byte
+}
+
+// ElasticWriter indexes entries through the _bulk API from a background goroutine, so Write
+// never waits on the cluster
+type ElasticWriter struct {
+	url        string
+	user       *url.Userinfo
+	index      string
+	batch      int
+	wait       time.Duration
+	deadLetter string
+	client     *http.Client
+	ch         chan elasticEntry
+	flush      chan chan struct{}
+	done       chan struct{}
+	stopped    chan struct{}
+	closeOnce  sync.Once
+	failing    atomic.Bool
+	mu         sync.Mutex // guards the dead letter file, written by Write and the sender
+}
+
+func NewElasticWriter(address string, cfg ElasticConfig) (*ElasticWriter, error) {
+	target, err := url.Parse(address)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("elastic: address %q is not an http:// or https:// URL", address)
+	}
+	x := &ElasticWriter{user: target.User, index: cfg.Index, batch: cfg.BatchSize, wait: ELASTIC_WAIT, deadLetter: cfg.DeadLetter,
+		client: &http.Client{Timeout: NET_DIAL_TIMEOUT * 6}, ch: make(chan elasticEntry, ELASTIC_BUFFER), flush: make(chan chan struct{}), done: make(chan struct{}), stopped: make(chan struct{})}
+	target.User = nil
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/_bulk"
+	x.url = target.String()
+	if x.index == "" {
+		x.index = ELASTIC_INDEX
+	}
+	if !validIndex(x.index) {
+		return nil, fmt.Errorf("elastic: index %q needs a closing } after its {", x.index)
+	}
+	if x.batch <= 0 {
+		x.batch = ELASTIC_BATCH
+	}
+	if cfg.BatchWait != "" {
+		if x.wait, err = time.ParseDuration(cfg.BatchWait); err != nil || x.wait <= 0 {
+			return nil, fmt.Errorf("elastic: batch_wait %q is not a positive duration", cfg.BatchWait)
+		}
+	}
+	go x.run()
+	return x, nil
+}
+
+func validIndex(index string) bool {
+	open := strings.Index(index, "{")
+	return open < 0 || strings.Index(index[open:], "}") > 0
+}
+
+// indexName formats the {...} part of the index template with t
+func indexName(template string, t time.Time) string {
+	open := strings.Index(template, "{")
+	if open < 0 {
+		return template
+	}
+	end := open + strings.Index(template[open:], "}")
+	return template[:open] + t.UTC().Format(template[open+1:end]) + template[end+1:]
+}
+
+// Write queues every line of p, lines that do not fit are written to the dead letter file
+func (x *ElasticWriter) Write(p []byte) (int, error) {
+	select {
+	case <-x.done:
+		return 0, errors.New("elastic writer closed")
+	default:
+	}
+	index := indexName(x.index, time.Now())
+	for _, line := range bytes.SplitAfter(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		item := elasticEntry{index: index, line: append([]byte{}, line...)}
+		if item.line[len(item.line)-1] != '\n' {
+			item.line = append(item.line, '\n')
+		}
+		select {
+		case x.ch <- item:
+		default:
+			x.reject([]elasticEntry{item})
+		}
+	}
+	return len(p), nil
+}
+
+func (x *ElasticWriter) run() {
+	defer close(x.stopped)
+	pending := []elasticEntry{}
+	timer := time.NewTimer(x.wait)
+	timer.Stop()
+	for {
+		select {
+		case item := <-x.ch:
+			if len(pending) == 0 {
+				timer.Reset(x.wait)
+			}
+			if pending = append(pending, item); len(pending) >= x.batch {
+				timer.Stop()
+				x.send(pending, true)
+				pending = pending[:0]
+			}
+		case <-timer.C:
+			x.send(pending, true)
+			pending = pending[:0]
+		case reply := <-x.flush:
+			timer.Stop()
+			pending = x.drain(pending)
+			close(reply)
+		case <-x.done:
+			timer.Stop()
+			x.drain(pending)
+			return
+		}
+	}
+}
+
+// drain sends pending and everything queued without retrying, for Flush and Close
+func (x *ElasticWriter) drain(pending []elasticEntry) []elasticEntry {
+	for len(x.ch) > 0 {
+		pending = append(pending, <-x.ch)
+	}
+	for len(pending) > 0 {
+		n := min(len(pending), x.batch)
+		x.send(pending[:n], false)
+		pending = pending[n:]
+	}
+	return pending[:0]
+}
+
+// send retries what the cluster could not take while retry is set, then dead letters it
+func (x *ElasticWriter) send(entries []elasticEntry, retry bool) {
+	backoff := NET_BACKOFF_MIN
+	for attempt := 0; len(entries) > 0; attempt++ {
+		again, failed, err := x.bulk(entries)
+		if err == nil {
+			if x.failing.Swap(false) {
+				diagnose("recover", x.url, nil)
+			}
+		} else if !x.failing.Swap(true) {
+			diagnose("bulk", x.url, err)
+		}
+		x.reject(failed)
+		if len(again) > 0 && (!retry || attempt == ELASTIC_RETRIES) {
+			x.reject(again)
+			return
+		}
+		entries = again
+		if len(entries) == 0 {
+			return
+		}
+		select {
+		case <-x.done:
+			retry = false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, NET_BACKOFF_MAX)
+	}
+}
+
+// bulk sends one _bulk request and splits the entries the cluster did not index into those
+// worth retrying, all of them when it was unreachable, and those it refused; err is only set
+// when the request itself failed
+func (x *ElasticWriter) bulk(entries []elasticEntry) (again, failed []elasticEntry, err error) {
+	body := []byte{}
+	for _, item := range entries {
+		body = append(body, `{"create":{"_index":`...)
+		body = append(appendString(body, item.index), "}}\n"...)
+		body = append(body, item.line...)
+	}
+	req, err := http.NewRequest(http.MethodPost, x.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, entries, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if x.user != nil {
+		password, _ := x.user.Password()
+		req.SetBasicAuth(x.user.Username(), password)
+	}
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return entries, nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return entries, nil, fmt.Errorf("unexpected status %s", resp.Status)
+	case resp.StatusCode/100 != 2:
+		return nil, entries, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	// NOTE a 200 can still carry per item failures, items are answered in request order
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<20)).Decode(&result); err != nil || !result.Errors {
+		return nil, nil, nil
+	}
+	var refused error
+	for i, item := range result.Items {
+		for _, outcome := range item {
+			switch {
+			case i >= len(entries) || outcome.Status/100 == 2:
+			case outcome.Status == http.StatusTooManyRequests:
+				again = append(again, entries[i])
+			default:
+				failed = append(failed, entries[i])
+				refused = fmt.Errorf("%d of %d entries refused, last with status %d: %s", len(failed), len(entries), outcome.Status, outcome.Error)
+			}
+		}
+	}
+	// NOTE refused entries are a mapping problem, not an outage, so they do not mark the writer failing
+	if refused != nil {
+		diagnose("index", x.url, refused)
+	}
+	return again, failed, nil
+}
+
+// reject appends entries to the dead letter file, without one they are dropped and counted
+func (x *ElasticWriter) reject(entries []elasticEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	if x.deadLetter == "" {
+		metricDropped.Add(uint64(len(entries)))
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	fh, err := os.OpenFile(x.deadLetter, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		diagnose("open", x.deadLetter, err)
+		metricDropped.Add(uint64(len(entries)))
+		return
+	}
+	defer fh.Close()
+	for _, item := range entries {
+		if _, err := fh.Write(item.line); err != nil {
+			diagnose("write", x.deadLetter, err)
+			metricDropped.Add(1)
+		}
+	}
+}
+
+// Flush sends what is pending before ctx is done, Logger.Flush calls it so a Fatal entry and
+// those before it are indexed before EXIT
+func (x *ElasticWriter) Flush(ctx context.Context) error {
+	return flushLoop(ctx, x.flush, x.stopped, "elastic")
+}
+
+// Close sends what is pending, waiting up to NET_CLOSE_TIMEOUT
+func (x *ElasticWriter) Close() error {
+	x.closeOnce.Do(func() { close(x.done) })
+	select {
+	case <-x.stopped:
+		return nil
+	case <-time.After(NET_CLOSE_TIMEOUT):
+		return errors.New("elastic writer: timed out sending pending entries")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func flushWriter(t *testing.T, w interface{ Flush(context.Context) error }) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := w.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestElasticBulkRequest(t *testing.T) {
+	server, requests := pushServer(t)
+	address := strings.Replace(server.URL, "http://", "http://elastic:secret@", 1)
+	w, err := NewElasticWriter(address, ElasticConfig{Index: "app-{2006}", BatchWait: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(`{"message":"one"}` + "\n" + `{"message":"two"}` + "\n"))
+	flushWriter(t, w)
+
+	item := push(t, requests)
+	if item.path != "/_bulk" || item.header.Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("bulk request to %s as %s", item.path, item.header.Get("Content-Type"))
+	}
+	if user, password, ok := (&http.Request{Header: item.header}).BasicAuth(); !ok || user != "elastic" || password != "secret" {
+		t.Fatalf("basic auth: %s %s %v", user, password, ok)
+	}
+	action := `{"create":{"_index":"app-` + time.Now().UTC().Format("2006") + `"}}`
+	expected := action + "\n" + `{"message":"one"}` + "\n" + action + "\n" + `{"message":"two"}` + "\n"
+	if string(item.body) != expected {
+		t.Fatalf("bulk body:\n%s", item.body)
+	}
+}
+
+func TestElasticDeadLettersRefusedItems(t *testing.T) {
+	quietDiagnostics(t)
+	attempts, count := make(chan []byte, 8), atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		attempts <- body
+		// NOTE the first item is indexed, the second refused and the third pushed back once
+		if count.Add(1) == 1 {
+			io.WriteString(w, `{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":400,"error":{"type":"mapper_parsing_exception"}}},{"create":{"status":429}}]}`)
+			return
+		}
+		io.WriteString(w, `{"errors":false,"items":[{"create":{"status":201}}]}`)
+	}))
+	defer server.Close()
+	deadLetter := filepath.Join(t.TempDir(), "dead.log")
+	w, err := NewElasticWriter(server.URL, ElasticConfig{BatchSize: 3, DeadLetter: deadLetter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(`{"message":"one"}` + "\n" + `{"message":"two"}` + "\n" + `{"message":"three"}` + "\n"))
+
+	<-attempts
+	select {
+	case retried := <-attempts:
+		if bytes.Count(retried, []byte("\n")) != 2 || !bytes.Contains(retried, []byte("three")) {
+			t.Fatalf("retry: %s", retried)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the pushed back item was not retried")
+	}
+	data, err := os.ReadFile(deadLetter)
+	if err != nil || string(data) != `{"message":"two"}`+"\n" {
+		t.Fatalf("dead letter file: %q %v", data, err)
+	}
+}
+
+func TestElasticIndexName(t *testing.T) {
+	at := time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC)
+	cases := map[string]string{
+		"logs":                  "logs",
+		ELASTIC_INDEX:           "logs-2025.03.09",
+		"app-{2006.01}-archive": "app-2025.03-archive",
+	}
+	for template, expected := range cases {
+		if name := indexName(template, at); name != expected {
+			t.Fatalf("%s: expected %s, got %s", template, expected, name)
+		}
+	}
+	if _, err := NewElasticWriter("http://es:9200", ElasticConfig{Index: "logs-{2006"}); err == nil {
+		t.Fatal("accepted an index without a closing }")
+	}
+}
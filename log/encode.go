@@ -0,0 +1,74 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// appendString appends s as a JSON string, control characters are escaped and invalid UTF-8
+// becomes U+FFFD so a line is always valid JSON whatever the input
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		// NOTE U+2028 and U+2029 are valid JSON but end a line in JavaScript
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// appendKey appends "key":
+func appendKey(dst []byte, key string) []byte {
+	return append(appendString(dst, key), ':')
+}
+
+// appendFloat writes NaN and infinities as strings since JSON has no literal for them
+func appendFloat(dst []byte, value float64, bits int) []byte {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return appendString(dst, strconv.FormatFloat(value, 'f', -1, bits))
+	}
+	return strconv.AppendFloat(dst, value, 'f', -1, bits)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// Encoder turns an entry into what a sink writes, Msg builds every entry once as a JSON line
+// and each sink encodes it for its format; lines that cannot be decoded are passed through
+type Encoder interface {
+	Encode(line []byte) []byte
+}
+
+// EncoderFunc lets a plain function be an Encoder
+type EncoderFunc func(line []byte) []byte
+
+func (x EncoderFunc) Encode(line []byte) []byte {
+	return x(line)
+}
+
+// LevelWriter is written with the level of each entry when a sink's writer implements it,
+// e.g. to map it to a syslog severity; such writers always get one entry per call
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level Level, p []byte) (int, error)
+}
+
+// NewEncoder builds the encoder of a sink, w is where it writes, e.g. to decide on colors
+type NewEncoder func(w io.Writer) Encoder
+
+// NOTE json is written as built, cbor and msgpack save bytes on disk and on the wire but not
+// encoding time; the index, chain, encryption, retention and OpenLog only read json files
+var encoders = map[string]NewEncoder{
+	"json":    func(io.Writer) Encoder { return nil },
+	"logfmt":  func(io.Writer) Encoder { return EncoderFunc(logfmt) },
+	"console": func(w io.Writer) Encoder { return NewConsoleWriter(w) },
+	"cbor":    func(io.Writer) Encoder { return EncoderFunc(encodeCBOR) },
+	"msgpack": func(io.Writer) Encoder { return EncoderFunc(encodeMsgpack) },
+}
+
+// RegisterEncoder adds a format for SinkConfig.Format
+func RegisterEncoder(name string, fn NewEncoder) {
+	encoders[strings.ToLower(name)] = fn
+}
+
+func encoderNames() string {
+	names := []string{}
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,218 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// NOTE an encrypted file is ENCRYPT_MAGIC, one byte key ID length and the key ID, followed by one
+// record per line: big-endian uint32 length, 12 byte nonce, AES-GCM ciphertext with the key ID as AAD
+const ENCRYPT_MAGIC = "SLOAN-ENC\x01"
+
+// NOTE the readers take lines of up to 16 MiB, a larger record length is a corrupt or forged file
+// and would otherwise be allocated as is
+const ENCRYPT_MAX_RECORD = 16 * 1024 * 1024
+
+// KeyProvider returns the 32 byte AES-256 key for a key ID, e.g. by asking a KMS
+type KeyProvider func(keyID string) ([]byte, error)
+
+var keyProviderMu sync.RWMutex
+var keyProvider KeyProvider = EnvKeyProvider
+
+// SetKeyProvider replaces EnvKeyProvider for encrypting sinks and the CLI tools
+func SetKeyProvider(fn KeyProvider) {
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+	keyProvider = fn
+}
+
+func lookupKey(keyID string) ([]byte, error) {
+	keyProviderMu.RLock()
+	fn := keyProvider
+	keyProviderMu.RUnlock()
+	key, err := fn(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key %q: expected 32 bytes, got %d", keyID, len(key))
+	}
+	return key, nil
+}
+
+// EnvKeyProvider reads the hex or base64 key from SLOAN_KEY_<ID>, the ID upper cased with
+// anything but letters and digits turned into '_'
+func EnvKeyProvider(keyID string) ([]byte, error) {
+	name := "SLOAN_KEY_" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, keyID)
+	text, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("key %q: %s is not set", keyID, name)
+	}
+	text = strings.TrimSpace(text)
+	if key, err := hex.DecodeString(text); err == nil {
+		return key, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %s is not hex or base64", keyID, name)
+	}
+	return key, nil
+}
+
+func newAEAD(keyID string) (cipher.AEAD, error) {
+	if len(keyID) == 0 || len(keyID) > 255 {
+		return nil, fmt.Errorf("key ID must be 1 to 255 bytes")
+	}
+	key, err := lookupKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptHeader(keyID string) []byte {
+	return append(append([]byte(ENCRYPT_MAGIC), byte(len(keyID))), keyID...)
+}
+
+func sealRecord(aead cipher.AEAD, keyID string, line []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	record := make([]byte, 4, 4+len(nonce)+len(line)+aead.Overhead())
+	record = append(record, nonce...)
+	record = aead.Seal(record, nonce, line, []byte(keyID))
+	binary.BigEndian.PutUint32(record[:4], uint32(len(record)-4))
+	return record
+}
+
+// readHeader returns the key ID of an encrypted file, or "" for a plaintext one
+func readHeader(r *bufio.Reader) (string, error) {
+	magic, err := r.Peek(len(ENCRYPT_MAGIC))
+	if err != nil || !bytes.Equal(magic, []byte(ENCRYPT_MAGIC)) {
+		return "", nil
+	}
+	r.Discard(len(ENCRYPT_MAGIC))
+	size, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	keyID := make([]byte, size)
+	if _, err := io.ReadFull(r, keyID); err != nil {
+		return "", err
+	}
+	return string(keyID), nil
+}
+
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	keyID   string
+	pending []byte
+}
+
+func (x *decryptReader) Read(p []byte) (int, error) {
+	for len(x.pending) == 0 {
+		var size [4]byte
+		if _, err := io.ReadFull(x.r, size[:]); err != nil {
+			return 0, err
+		}
+		length := binary.BigEndian.Uint32(size[:])
+		if length > ENCRYPT_MAX_RECORD {
+			return 0, fmt.Errorf("encrypted record of %d bytes exceeds %d", length, ENCRYPT_MAX_RECORD)
+		}
+		record := make([]byte, length)
+		if _, err := io.ReadFull(x.r, record); err != nil {
+			return 0, fmt.Errorf("truncated encrypted record: %w", err)
+		}
+		if len(record) < x.aead.NonceSize() {
+			return 0, errors.New("short encrypted record")
+		}
+		nonce := record[:x.aead.NonceSize()]
+		plain, err := x.aead.Open(nil, nonce, record[len(nonce):], []byte(x.keyID))
+		if err != nil {
+			return 0, fmt.Errorf("decrypt record: %w", err)
+		}
+		x.pending = plain
+	}
+	n := copy(p, x.pending)
+	x.pending = x.pending[n:]
+	return n, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// OpenLog opens a log file for reading, decrypting it with the key provider when it is encrypted
+func OpenLog(path string) (io.ReadCloser, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewLogReader(fh)
+	if err != nil {
+		fh.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return readCloser{r, fh}, nil
+}
+
+// NewLogReader passes plaintext logs through and decrypts encrypted ones
+func NewLogReader(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	keyID, err := readHeader(buffered)
+	if err != nil {
+		return nil, err
+	}
+	if keyID == "" {
+		return buffered, nil
+	}
+	aead, err := newAEAD(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: buffered, aead: aead, keyID: keyID}, nil
+}
+
+// checkHeader makes sure an existing file can take records for keyID
+func checkHeader(path, keyID string) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	found, err := readHeader(bufio.NewReader(fh))
+	if err != nil {
+		return err
+	}
+	if found == "" {
+		return fmt.Errorf("%s is not encrypted, cannot append encrypted records", path)
+	}
+	if found != keyID {
+		return fmt.Errorf("%s is encrypted with key %q, not %q", path, found, keyID)
+	}
+	return nil
+}
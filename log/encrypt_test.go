@@ -0,0 +1,191 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testKeys serves a fixed key for every key ID starting with "k"
+func testKeys(t *testing.T) {
+	SetKeyProvider(func(keyID string) ([]byte, error) {
+		if keyID == "" || keyID[0] != 'k' {
+			return nil, fmt.Errorf("no key %q", keyID)
+		}
+		return bytes.Repeat([]byte(keyID[:1]), 32), nil
+	})
+	t.Cleanup(func() { SetKeyProvider(EnvKeyProvider) })
+}
+
+// writeEncrypted appends one chained line per message to path, encrypted with keyID
+func writeEncrypted(t *testing.T, path, keyID string, messages ...string) {
+	t.Helper()
+	fh, err := NewRotatingFile(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fh.EnableChain(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fh.EnableEncryption(keyID); err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range messages {
+		if _, err := fh.Write([]byte(`{"message":"` + message + `"}` + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fh.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// editRecords rewrites the records of an encrypted file with fn, the header stays
+func editRecords(t *testing.T, path, keyID string, fn func([][]byte) [][]byte) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := len(encryptHeader(keyID))
+	records := [][]byte{}
+	for rest := data[header:]; len(rest) > 0; {
+		size := 4 + int(binary.BigEndian.Uint32(rest[:4]))
+		records = append(records, rest[:size])
+		rest = rest[size:]
+	}
+	out := append([]byte{}, data[:header]...)
+	out = append(out, bytes.Join(fn(records), nil)...)
+	if err := os.WriteFile(path, out, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readEncrypted(path string) (*ChainReport, error) {
+	r, err := OpenLog(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return VerifyChain(r, nil)
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	testKeys(t)
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeEncrypted(t, path, "k1", "one", "two")
+	writeEncrypted(t, path, "k1", "three")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte(ENCRYPT_MAGIC)) || bytes.Contains(data, []byte("message")) {
+		t.Fatal("file is not encrypted")
+	}
+	report, err := readEncrypted(path)
+	if err != nil || !report.OK || report.Entries != 3 {
+		t.Fatalf("encrypted chain: %+v %v", report, err)
+	}
+}
+
+func TestEncryptRefusesOtherKey(t *testing.T) {
+	testKeys(t)
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeEncrypted(t, path, "k1", "one")
+	fh, err := NewRotatingFile(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	if err := fh.EnableEncryption("k2"); err == nil {
+		t.Fatal("appended to a file encrypted with another key")
+	}
+}
+
+func TestEncryptDetectsTampering(t *testing.T) {
+	cases := map[string]func([][]byte) [][]byte{
+		"modified": func(records [][]byte) [][]byte {
+			records[1][len(records[1])-1] ^= 1
+			return records
+		},
+		"truncated": func(records [][]byte) [][]byte {
+			records[2] = records[2][:len(records[2])-5]
+			return records
+		},
+	}
+	for name, edit := range cases {
+		t.Run(name, func(t *testing.T) {
+			testKeys(t)
+			path := filepath.Join(t.TempDir(), "a.log")
+			writeEncrypted(t, path, "k1", "one", "two", "three")
+			editRecords(t, path, "k1", edit)
+			if _, err := readEncrypted(path); err == nil || err == io.EOF {
+				t.Fatalf("expected a read error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEncryptRefusesOversizedRecord(t *testing.T) {
+	testKeys(t)
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeEncrypted(t, path, "k1", "one")
+	editRecords(t, path, "k1", func(records [][]byte) [][]byte {
+		binary.BigEndian.PutUint32(records[0][:4], 0xffffffff)
+		return records
+	})
+	if _, err := readEncrypted(path); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected the record to be refused, got %v", err)
+	}
+}
+
+func TestEncryptDetectsReordering(t *testing.T) {
+	cases := map[string]func([][]byte) [][]byte{
+		"reordered": func(records [][]byte) [][]byte {
+			records[1], records[2] = records[2], records[1]
+			return records
+		},
+		"deleted": func(records [][]byte) [][]byte {
+			return append(records[:1:1], records[2:]...)
+		},
+	}
+	for name, edit := range cases {
+		t.Run(name, func(t *testing.T) {
+			testKeys(t)
+			path := filepath.Join(t.TempDir(), "a.log")
+			writeEncrypted(t, path, "k1", "one", "two", "three")
+			editRecords(t, path, "k1", edit)
+			// NOTE every record decrypts on its own, the chain inside them catches the order
+			report, err := readEncrypted(path)
+			if err != nil || report.OK || report.Line != 2 {
+				t.Fatalf("expected a break at line 2: %+v %v", report, err)
+			}
+		})
+	}
+}
+
+func TestEncryptMovesTornFileAside(t *testing.T) {
+	testKeys(t)
+	quietDiagnostics(t)
+	path := filepath.Join(t.TempDir(), "a.log")
+	writeEncrypted(t, path, "k1", "one", "two")
+	editRecords(t, path, "k1", func(records [][]byte) [][]byte {
+		records[1] = records[1][:3]
+		return records
+	})
+	writeEncrypted(t, path, "k1", "three")
+	report, err := readEncrypted(path)
+	if err != nil || !report.OK || report.Entries != 1 {
+		t.Fatalf("new chain: %+v %v", report, err)
+	}
+	if aside, _ := filepath.Glob(path + ".broken-*"); len(aside) != 1 {
+		t.Fatalf("expected the torn file moved aside, found %v", aside)
+	}
+}
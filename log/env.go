@@ -0,0 +1,389 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SLOAN_SINKS is a comma separated list of type[:path][@level], e.g. "stderr@warn,file:/var/log/app.log"
+// SLOAN_LEVELS and SLOAN_STATIC_FIELDS are comma separated key=value lists, e.g. "dns=debug,http=warn"
+// SLOAN_REDACT_KEYS, SLOAN_SAMPLE_LEVELS and SLOAN_DEDUP_FIELDS are comma separated, SLOAN_REDACT_PATTERNS is ';' separated
+// SLOAN_SINK_<n>_<OPTION> sets an option of the n-th sink counted from 1, OPTION is the upper cased key of
+// the config file prefixed by its section, e.g. SLOAN_SINK_1_CHAIN=true or SLOAN_SINK_2_LOKI_LABELS="app=api"
+// SLOAN_ENCRYPT_FIELDS is a comma separated field=keyID list; components only take SLOAN_LEVELS, their
+// sinks and sampling are set in the config file
+const (
+	ENV_PROFILE         = "SLOAN_PROFILE"
+	ENV_LEVEL           = "SLOAN_LEVEL"
+	ENV_LEVELS          = "SLOAN_LEVELS"
+	ENV_STATIC_FIELDS   = "SLOAN_STATIC_FIELDS"
+	ENV_FORMAT          = "SLOAN_FORMAT"
+	ENV_CALLER          = "SLOAN_CALLER"
+	ENV_CALLER_SKIP     = "SLOAN_CALLER_SKIP"
+	ENV_CALLER_TRIM     = "SLOAN_CALLER_TRIM"
+	ENV_CALLER_FUNC     = "SLOAN_CALLER_FUNC"
+	ENV_SEQUENCE        = "SLOAN_SEQUENCE"
+	ENV_DRY_RUN         = "SLOAN_DRY_RUN"
+	ENV_SINKS           = "SLOAN_SINKS"
+	ENV_TIMESTAMP       = "SLOAN_TIMESTAMP"
+	ENV_UTC             = "SLOAN_UTC"
+	ENV_TIME_KEY        = "SLOAN_TIME_KEY"
+	ENV_LEVEL_KEY       = "SLOAN_LEVEL_KEY"
+	ENV_MESSAGE_KEY     = "SLOAN_MESSAGE_KEY"
+	ENV_DURATION_UNIT   = "SLOAN_DURATION_UNIT"
+	ENV_TIME_LAYOUT     = "SLOAN_TIME_LAYOUT"
+	ENV_ERROR_STACK     = "SLOAN_ERROR_STACK_TRACES"
+	ENV_ERROR_DEPTH     = "SLOAN_ERROR_STACK_DEPTH"
+	ENV_ROTATE_SIZE     = "SLOAN_ROTATE_MAX_SIZE_MB"
+	ENV_ROTATE_BACKUPS  = "SLOAN_ROTATE_MAX_BACKUPS"
+	ENV_ROTATE_AGE      = "SLOAN_ROTATE_MAX_AGE_DAYS"
+	ENV_ROTATE_INTERVAL = "SLOAN_ROTATE_INTERVAL"
+	ENV_ROTATE_COMPRESS = "SLOAN_ROTATE_COMPRESS"
+	ENV_REDACT_KEYS     = "SLOAN_REDACT_KEYS"
+	ENV_REDACT_PATTERNS = "SLOAN_REDACT_PATTERNS"
+	ENV_REDACT_MASK     = "SLOAN_REDACT_MASK"
+	ENV_SAMPLE_EVERY    = "SLOAN_SAMPLE_EVERY"
+	ENV_SAMPLE_LEVELS   = "SLOAN_SAMPLE_LEVELS"
+	ENV_SAMPLE_BURST    = "SLOAN_SAMPLE_BURST"
+	ENV_SAMPLE_PERIOD   = "SLOAN_SAMPLE_PERIOD"
+	ENV_DEDUP_WINDOW    = "SLOAN_DEDUP_WINDOW"
+	ENV_DEDUP_FIELDS    = "SLOAN_DEDUP_FIELDS"
+	ENV_ASYNC           = "SLOAN_ASYNC"
+	ENV_ASYNC_BUFFER    = "SLOAN_ASYNC_BUFFER"
+	ENV_ASYNC_POLICY    = "SLOAN_ASYNC_POLICY"
+	ENV_AUDIT_PATH      = "SLOAN_AUDIT_PATH"
+	ENV_AUDIT_CHAIN     = "SLOAN_AUDIT_CHAIN"
+	ENV_AUDIT_KEY       = "SLOAN_AUDIT_SIGNING_KEY"
+	ENV_AUDIT_ENCRYPT   = "SLOAN_AUDIT_ENCRYPT"
+	ENV_AUDIT_WORM      = "SLOAN_AUDIT_WORM"
+	ENV_AUDIT_HMAC      = "SLOAN_AUDIT_HMAC"
+	ENV_RETENTION_DAYS  = "SLOAN_RETENTION_DAYS"
+	ENV_RETENTION_ACT   = "SLOAN_RETENTION_ACTION"
+	ENV_ENCRYPT_FIELDS  = "SLOAN_ENCRYPT_FIELDS"
+	ENV_SINK_PREFIX     = "SLOAN_SINK_"
+)
+
+// sinkOptions maps the OPTION of SLOAN_SINK_<n>_<OPTION> to the field it sets
+var sinkOptions = map[string]func(x *SinkConfig) interface{}{
+	"PATH":                 func(x *SinkConfig) interface{} { return &x.Path },
+	"LEVEL":                func(x *SinkConfig) interface{} { return &x.Level },
+	"FORMAT":               func(x *SinkConfig) interface{} { return &x.Format },
+	"INDEX":                func(x *SinkConfig) interface{} { return &x.Index },
+	"CHAIN":                func(x *SinkConfig) interface{} { return &x.Chain },
+	"SIGNING_KEY":          func(x *SinkConfig) interface{} { return &x.SigningKey },
+	"ENCRYPT":              func(x *SinkConfig) interface{} { return &x.Encrypt },
+	"WORM":                 func(x *SinkConfig) interface{} { return &x.WORM },
+	"HMAC":                 func(x *SinkConfig) interface{} { return &x.HMAC },
+	"PRETTY":               func(x *SinkConfig) interface{} { return &x.Pretty },
+	"SYSLOG_FACILITY":      func(x *SinkConfig) interface{} { return &x.Syslog.Facility },
+	"SYSLOG_FORMAT":        func(x *SinkConfig) interface{} { return &x.Syslog.Format },
+	"SYSLOG_TAG":           func(x *SinkConfig) interface{} { return &x.Syslog.Tag },
+	"LOKI_LABELS":          func(x *SinkConfig) interface{} { return &x.Loki.Labels },
+	"LOKI_BATCH_SIZE":      func(x *SinkConfig) interface{} { return &x.Loki.BatchSize },
+	"LOKI_BATCH_WAIT":      func(x *SinkConfig) interface{} { return &x.Loki.BatchWait },
+	"LOKI_TENANT":          func(x *SinkConfig) interface{} { return &x.Loki.Tenant },
+	"ELASTIC_INDEX":        func(x *SinkConfig) interface{} { return &x.Elastic.Index },
+	"ELASTIC_BATCH_SIZE":   func(x *SinkConfig) interface{} { return &x.Elastic.BatchSize },
+	"ELASTIC_BATCH_WAIT":   func(x *SinkConfig) interface{} { return &x.Elastic.BatchWait },
+	"ELASTIC_DEAD_LETTER":  func(x *SinkConfig) interface{} { return &x.Elastic.DeadLetter },
+	"WEBHOOK_TEMPLATE":     func(x *SinkConfig) interface{} { return &x.Webhook.Template },
+	"WEBHOOK_CONTENT_TYPE": func(x *SinkConfig) interface{} { return &x.Webhook.ContentType },
+	"WEBHOOK_MATCH":        func(x *SinkConfig) interface{} { return &x.Webhook.Match },
+	"WEBHOOK_AUTH":         func(x *SinkConfig) interface{} { return &x.Webhook.Auth },
+	"WEBHOOK_LIMIT":        func(x *SinkConfig) interface{} { return &x.Webhook.Limit },
+	"WEBHOOK_PERIOD":       func(x *SinkConfig) interface{} { return &x.Webhook.Period },
+	"OTLP_PROTOCOL":        func(x *SinkConfig) interface{} { return &x.OTLP.Protocol },
+	"OTLP_HEADERS":         func(x *SinkConfig) interface{} { return &x.OTLP.Headers },
+	"OTLP_RESOURCE":        func(x *SinkConfig) interface{} { return &x.OTLP.Resource },
+	"OTLP_BATCH_SIZE":      func(x *SinkConfig) interface{} { return &x.OTLP.BatchSize },
+	"OTLP_BATCH_WAIT":      func(x *SinkConfig) interface{} { return &x.OTLP.BatchWait },
+}
+
+// ConfigFromEnv overlays any SLOAN_* variables that are set onto cfg
+func ConfigFromEnv(cfg Config) (Config, error) {
+	if value, ok := os.LookupEnv(ENV_PROFILE); ok {
+		cfg.Profile = value
+	}
+	if value, ok := os.LookupEnv(ENV_LEVEL); ok {
+		cfg.Level = value
+	}
+	if value, ok := os.LookupEnv(ENV_LEVELS); ok {
+		levels, err := parsePairs(ENV_LEVELS, value)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Levels = levels
+	}
+	if value, ok := os.LookupEnv(ENV_STATIC_FIELDS); ok {
+		fields, err := parsePairs(ENV_STATIC_FIELDS, value)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.StaticFields = fields
+	}
+	if value, ok := os.LookupEnv(ENV_FORMAT); ok {
+		cfg.Format = value
+	}
+	if err := envBool(ENV_CALLER, &cfg.Caller); err != nil {
+		return cfg, err
+	}
+	if err := envInt(ENV_CALLER_SKIP, &cfg.CallerSkip); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_CALLER_TRIM); ok {
+		cfg.CallerTrim = value
+	}
+	if err := envBool(ENV_CALLER_FUNC, &cfg.CallerFunc); err != nil {
+		return cfg, err
+	}
+	if err := envBool(ENV_SEQUENCE, &cfg.Sequence); err != nil {
+		return cfg, err
+	}
+	if err := envBool(ENV_DRY_RUN, &cfg.DryRun); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_SINKS); ok {
+		cfg.Sinks = parseSinks(value)
+	}
+	if err := envSinkOptions(&cfg); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_TIMESTAMP); ok {
+		cfg.Timestamp = value
+	}
+	if err := envBool(ENV_UTC, &cfg.UTC); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_TIME_KEY); ok {
+		cfg.TimeKey = value
+	}
+	if value, ok := os.LookupEnv(ENV_LEVEL_KEY); ok {
+		cfg.LevelKey = value
+	}
+	if value, ok := os.LookupEnv(ENV_MESSAGE_KEY); ok {
+		cfg.MessageKey = value
+	}
+	if value, ok := os.LookupEnv(ENV_DURATION_UNIT); ok {
+		cfg.DurationUnit = value
+	}
+	if value, ok := os.LookupEnv(ENV_TIME_LAYOUT); ok {
+		cfg.TimeLayout = value
+	}
+	if err := envBool(ENV_ERROR_STACK, &cfg.ErrorStackTraces); err != nil {
+		return cfg, err
+	}
+	if err := envInt(ENV_ERROR_DEPTH, &cfg.ErrorStackDepth); err != nil {
+		return cfg, err
+	}
+	if err := envInt(ENV_ROTATE_SIZE, &cfg.Rotation.MaxSizeMB); err != nil {
+		return cfg, err
+	}
+	if err := envInt(ENV_ROTATE_BACKUPS, &cfg.Rotation.MaxBackups); err != nil {
+		return cfg, err
+	}
+	if err := envInt(ENV_ROTATE_AGE, &cfg.Rotation.MaxAgeDays); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_ROTATE_INTERVAL); ok {
+		cfg.Rotation.Interval = value
+	}
+	if err := envBool(ENV_ROTATE_COMPRESS, &cfg.Rotation.Compress); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_REDACT_KEYS); ok {
+		cfg.Redact.Keys = splitList(value, ",")
+	}
+	if value, ok := os.LookupEnv(ENV_REDACT_PATTERNS); ok {
+		cfg.Redact.Patterns = splitList(value, ";")
+	}
+	if value, ok := os.LookupEnv(ENV_REDACT_MASK); ok {
+		cfg.Redact.Mask = value
+	}
+	if err := envInt(ENV_SAMPLE_EVERY, &cfg.Sampling.Every); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_SAMPLE_LEVELS); ok {
+		cfg.Sampling.Levels = splitList(value, ",")
+	}
+	if err := envInt(ENV_SAMPLE_BURST, &cfg.Sampling.Burst); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_SAMPLE_PERIOD); ok {
+		cfg.Sampling.Period = value
+	}
+	if value, ok := os.LookupEnv(ENV_DEDUP_WINDOW); ok {
+		cfg.Dedup.Window = value
+	}
+	if value, ok := os.LookupEnv(ENV_DEDUP_FIELDS); ok {
+		cfg.Dedup.Fields = splitList(value, ",")
+	}
+	if err := envBool(ENV_ASYNC, &cfg.Async.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := envInt(ENV_ASYNC_BUFFER, &cfg.Async.Buffer); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_ASYNC_POLICY); ok {
+		cfg.Async.Policy = value
+	}
+	if value, ok := os.LookupEnv(ENV_AUDIT_PATH); ok {
+		cfg.Audit.Path = value
+	}
+	if err := envBool(ENV_AUDIT_CHAIN, &cfg.Audit.Chain); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_AUDIT_KEY); ok {
+		cfg.Audit.SigningKey = value
+	}
+	if value, ok := os.LookupEnv(ENV_AUDIT_ENCRYPT); ok {
+		cfg.Audit.Encrypt = value
+	}
+	if err := envBool(ENV_AUDIT_WORM, &cfg.Audit.WORM); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_AUDIT_HMAC); ok {
+		cfg.Audit.HMAC = value
+	}
+	if err := envInt(ENV_RETENTION_DAYS, &cfg.Retention.Days); err != nil {
+		return cfg, err
+	}
+	if value, ok := os.LookupEnv(ENV_RETENTION_ACT); ok {
+		cfg.Retention.Action = value
+	}
+	if value, ok := os.LookupEnv(ENV_ENCRYPT_FIELDS); ok {
+		fields, err := parsePairs(ENV_ENCRYPT_FIELDS, value)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.EncryptFields = fields
+	}
+	return cfg, nil
+}
+
+func InitFromEnv() error {
+	cfg, err := ConfigFromEnv(Config{})
+	if err != nil {
+		return err
+	}
+	return Init(cfg)
+}
+
+func parseSinks(value string) []SinkConfig {
+	sinks := []SinkConfig{}
+	for _, item := range splitList(value, ",") {
+		cfg := SinkConfig{}
+		if i := strings.LastIndex(item, "@"); i >= 0 {
+			cfg.Level = item[i+1:]
+			item = item[:i]
+		}
+		cfg.Type, cfg.Path, _ = strings.Cut(item, ":")
+		sinks = append(sinks, cfg)
+	}
+	return sinks
+}
+
+// envSinkOptions applies SLOAN_SINK_<n>_<OPTION> to the sinks of SLOAN_SINKS or the config file
+func envSinkOptions(cfg *Config) error {
+	copied := false
+	for _, item := range os.Environ() {
+		name, value, _ := strings.Cut(item, "=")
+		if !strings.HasPrefix(name, ENV_SINK_PREFIX) {
+			continue
+		}
+		index, option, _ := strings.Cut(strings.TrimPrefix(name, ENV_SINK_PREFIX), "_")
+		n, err := strconv.Atoi(index)
+		if err != nil || n < 1 || n > len(cfg.Sinks) {
+			return fmt.Errorf("%s: there is no sink %s", name, index)
+		}
+		field, ok := sinkOptions[option]
+		if !ok {
+			return fmt.Errorf("%s: unknown sink option %s", name, option)
+		}
+		// NOTE the sinks may be those of the caller's config
+		if !copied {
+			cfg.Sinks, copied = append([]SinkConfig{}, cfg.Sinks...), true
+		}
+		if err := setOption(name, value, field(&cfg.Sinks[n-1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setOption(name, text string, field interface{}) error {
+	switch value := field.(type) {
+	case *string:
+		*value = text
+	case *bool:
+		return parseBool(name, text, value)
+	case *int:
+		return parseInt(name, text, value)
+	case *map[string]string:
+		pairs, err := parsePairs(name, text)
+		if err != nil {
+			return err
+		}
+		*value = pairs
+	}
+	return nil
+}
+
+// parsePairs reads a comma separated list of key=value
+func parsePairs(name, value string) (map[string]string, error) {
+	pairs := map[string]string{}
+	for _, item := range splitList(value, ",") {
+		key, text, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s: %q is not key=value", name, item)
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(text)
+	}
+	return pairs, nil
+}
+
+func splitList(value, sep string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func envInt(name string, value *int) error {
+	text, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	return parseInt(name, text, value)
+}
+
+func parseInt(name, text string, value *int) error {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a number", name, text)
+	}
+	*value = n
+	return nil
+}
+
+func envBool(name string, value *bool) error {
+	text, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	return parseBool(name, text, value)
+}
+
+func parseBool(name, text string, value *bool) error {
+	b, err := strconv.ParseBool(strings.TrimSpace(text))
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a boolean", name, text)
+	}
+	*value = b
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "testing"
+
+func TestSinkOptionsFromEnv(t *testing.T) {
+	t.Setenv(ENV_SINKS, "stderr@warn,loki:http://loki:3100")
+	t.Setenv("SLOAN_SINK_1_FORMAT", "logfmt")
+	t.Setenv("SLOAN_SINK_2_LOKI_LABELS", "app=api,env=prod")
+	t.Setenv("SLOAN_SINK_2_LOKI_BATCH_SIZE", "50")
+	t.Setenv(ENV_ENCRYPT_FIELDS, "ssn=k1")
+	cfg, err := ConfigFromEnv(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Sinks[0].Format != "logfmt" || cfg.Sinks[0].Level != "warn" {
+		t.Fatalf("stderr sink: %+v", cfg.Sinks[0])
+	}
+	if loki := cfg.Sinks[1].Loki; loki.BatchSize != 50 || loki.Labels["env"] != "prod" {
+		t.Fatalf("loki sink: %+v", loki)
+	}
+	if cfg.EncryptFields["ssn"] != "k1" {
+		t.Fatalf("encrypt fields: %v", cfg.EncryptFields)
+	}
+}
+
+func TestSinkOptionsFromEnvErrors(t *testing.T) {
+	cases := map[string]string{
+		"SLOAN_SINK_3_FORMAT": "json",
+		"SLOAN_SINK_1_COLOR":  "true",
+		"SLOAN_SINK_1_CHAIN":  "sometimes",
+	}
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(ENV_SINKS, "file:/var/log/app.log")
+			t.Setenv(name, value)
+			if _, err := ConfigFromEnv(Config{}); err == nil {
+				t.Fatalf("expected %s=%s to fail", name, value)
+			}
+		})
+	}
+}
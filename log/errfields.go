@@ -0,0 +1,93 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+)
+
+// ERROR_STACK_DEPTH is the number of frames Err records when Config.ErrorStackDepth is 0
+const ERROR_STACK_DEPTH = 32
+
+// structured is implemented by errors from the sloan errors package
+type structured interface {
+	Code() string
+	Fields() map[string]interface{}
+}
+
+// errFields writes the code as "error_code" and every field of a structured error in err
+func (x *Event) errFields(err error) {
+	var item structured
+	if !errors.As(err, &item) {
+		return
+	}
+	if code := item.Code(); code != "" {
+		x.Str("error_code", code)
+	}
+	fields := item.Fields()
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		x.Str(key, fmt.Sprint(fields[key]))
+	}
+}
+
+// errChain writes "error_chain" with the text of err and of every cause below it, joined
+// errors are walked depth first; a single error has no chain
+func (x *Event) errChain(err error) {
+	chain := []string{}
+	var walk func(error)
+	walk = func(err error) {
+		for err != nil {
+			chain = append(chain, redactText(x.st, err.Error()))
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, item := range joined.Unwrap() {
+					walk(item)
+				}
+				return
+			}
+			err = errors.Unwrap(err)
+		}
+	}
+	walk(err)
+	if len(chain) < 2 {
+		return
+	}
+	buffer := append(appendKey(append(x.buf, ','), "error_chain"), '[')
+	for i, text := range chain {
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		buffer = appendString(buffer, text)
+	}
+	x.buf = append(buffer, ']')
+}
+
+// errStack writes "error_stack" as "dir/file.go:line function" frames, starting at the caller of Err
+func (x *Event) errStack(depth int) {
+	if depth <= 0 {
+		depth = ERROR_STACK_DEPTH
+	}
+	pcs := make([]uintptr, depth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	buffer := append(appendKey(append(x.buf, ','), "error_stack"), '[')
+	for i := 0; ; i++ {
+		frame, more := frames.Next()
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		site := filepath.Base(filepath.Dir(frame.File)) + "/" + filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		buffer = appendString(buffer, site+" "+frame.Function)
+		if !more {
+			break
+		}
+	}
+	x.buf = append(buffer, ']')
+}
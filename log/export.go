@@ -0,0 +1,127 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Column maps an entry field ("time", "level", "message" or any field key) to an output column
+type Column struct {
+	Name  string
+	Field string
+}
+
+// ParseColumns reads "name=field" or "field" items separated by commas, e.g. "ts=time,level,target"
+func ParseColumns(spec string) []Column {
+	columns := []Column{}
+	for _, item := range splitList(spec, ",") {
+		name, field, ok := strings.Cut(item, "=")
+		if !ok {
+			field = name
+		}
+		columns = append(columns, Column{Name: name, Field: field})
+	}
+	return columns
+}
+
+// RowWriter receives one row per entry, the header is written by the ExportFormat constructor
+type RowWriter interface {
+	Write(row []string) error
+	Close() error
+}
+
+type ExportFormat func(w io.Writer, columns []Column) (RowWriter, error)
+
+// NOTE only csv and tsv are built in, register "parquet" backed by a parquet library to avoid the dependency here
+var exportFormats = map[string]ExportFormat{
+	"csv": func(w io.Writer, columns []Column) (RowWriter, error) { return newDelimited(w, columns, ',') },
+	"tsv": func(w io.Writer, columns []Column) (RowWriter, error) { return newDelimited(w, columns, '\t') },
+}
+
+func RegisterExportFormat(name string, fn ExportFormat) {
+	exportFormats[strings.ToLower(name)] = fn
+}
+
+type delimited struct {
+	w *csv.Writer
+}
+
+func newDelimited(w io.Writer, columns []Column, comma rune) (RowWriter, error) {
+	x := &delimited{w: csv.NewWriter(w)}
+	x.w.Comma = comma
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	return x, x.w.Write(header)
+}
+
+func (x *delimited) Write(row []string) error {
+	return x.w.Write(row)
+}
+
+func (x *delimited) Close() error {
+	x.w.Flush()
+	return x.w.Error()
+}
+
+// Export writes every entry of src as a row and returns how many rows were written
+func Export(src EntrySource, w io.Writer, format string, columns []Column) (int, error) {
+	build, ok := exportFormats[strings.ToLower(format)]
+	if !ok {
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+	if len(columns) == 0 {
+		columns = ParseColumns("time,level,message")
+	}
+	out, err := build(w, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	row := make([]string, len(columns))
+	for {
+		entry, err := src.Decode()
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, ErrMalformedEntry) {
+			continue
+		}
+		if err != nil {
+			out.Close()
+			return rows, err
+		}
+		for i, column := range columns {
+			row[i] = entry.column(column.Field)
+		}
+		if err := out.Write(row); err != nil {
+			out.Close()
+			return rows, err
+		}
+		rows++
+	}
+	return rows, out.Close()
+}
+
+func (x *Entry) column(field string) string {
+	switch field {
+	case "time":
+		if x.Time.IsZero() {
+			return ""
+		}
+		return x.Time.Format(time.RFC3339Nano)
+	case "level":
+		return x.Level
+	case "message":
+		return x.Message
+	}
+	value, _ := x.Field(field)
+	return value
+}
@@ -0,0 +1,15 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "expvar"
+
+// NOTE importing expvar serves /debug/vars on http.DefaultServeMux, the metrics show up as "sloan"
+func init() {
+	expvar.Publish("sloan", expvar.Func(func() interface{} {
+		return map[string]interface{}{
+			"metrics": ReadMetrics(),
+			"health":  Health(),
+			"level":   levelName(std.mask()),
+		}
+	}))
+}
@@ -0,0 +1,98 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// NOTE an encrypted field value is "enc:v1:<key id>:<base64 nonce+ciphertext>" sealed with the
+// field key as AAD, so a value cannot be moved to another field unnoticed
+const FIELD_CIPHER_PREFIX = "enc:v1:"
+
+type fieldCipher struct {
+	keyID string
+	aead  cipher.AEAD
+}
+
+// buildFieldCiphers maps each field key to the cipher of its key ID
+func buildFieldCiphers(fields map[string]string) (map[string]*fieldCipher, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	byKeyID := map[string]*fieldCipher{}
+	ciphers := map[string]*fieldCipher{}
+	for field, keyID := range fields {
+		x, ok := byKeyID[keyID]
+		if !ok {
+			aead, err := newAEAD(keyID)
+			if err != nil {
+				return nil, fmt.Errorf("encrypt_fields.%s: %w", field, err)
+			}
+			x = &fieldCipher{keyID: keyID, aead: aead}
+			byKeyID[keyID] = x
+		}
+		ciphers[strings.ToLower(field)] = x
+	}
+	return ciphers, nil
+}
+
+func encryptValue(st *state, key, value string) string {
+	x := st.fieldCiphers[strings.ToLower(key)]
+	if x == nil {
+		return value
+	}
+	nonce := make([]byte, x.aead.NonceSize())
+	rand.Read(nonce)
+	sealed := x.aead.Seal(nonce, nonce, []byte(value), []byte(key))
+	return FIELD_CIPHER_PREFIX + x.keyID + ":" + base64.StdEncoding.EncodeToString(sealed)
+}
+
+// DecryptField opens a value written for field key, values that are not encrypted are returned as is
+func DecryptField(key, value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, FIELD_CIPHER_PREFIX)
+	if !ok {
+		return value, nil
+	}
+	keyID, text, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", fmt.Errorf("field %s: malformed encrypted value", key)
+	}
+	aead, err := newAEAD(keyID)
+	if err != nil {
+		return "", fmt.Errorf("field %s: %w", key, err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(text)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("field %s: malformed encrypted value", key)
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("field %s: %w", key, err)
+	}
+	return string(plain), nil
+}
+
+// Decrypt replaces every encrypted field the KeyProvider has a key for, and reports the others
+func (x *Entry) Decrypt() error {
+	problems := []string{}
+	for key, value := range x.Fields {
+		text, ok := value.(string)
+		if !ok || !strings.HasPrefix(text, FIELD_CIPHER_PREFIX) {
+			continue
+		}
+		plain, err := DecryptField(key, text)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		x.Fields[key] = plain
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NOTE Bytes and Hex write at most BYTES_MAX bytes of a value, a cut value gets a <key>_len
+// field with the full length
+const BYTES_MAX = 1024
+
+// NOTE Config.DurationUnit picks the unit Dur writes in, Config.TimeLayout the layout of Time
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+}
+
+func (x *Event) Uint64(key string, value uint64) ILogger {
+	if x.ignore {
+		return x
+	}
+	x.buf = strconv.AppendUint(appendKey(append(x.buf, ','), key), value, 10)
+	return x
+}
+
+func (x *Event) Float64(key string, value float64) ILogger {
+	if x.ignore {
+		return x
+	}
+	x.buf = appendFloat(appendKey(append(x.buf, ','), key), value, 64)
+	return x
+}
+
+// Hex writes value hex encoded, e.g. a hash or fingerprint
+func (x *Event) Hex(key string, value []byte) ILogger {
+	if x.ignore {
+		return x
+	}
+	x.buf = append(appendKey(append(x.buf, ','), key), '"')
+	x.buf = append(hex.AppendEncode(x.buf, cut(value)), '"')
+	return x.cutLen(key, value)
+}
+
+// Bytes writes value as a string, bytes that are not UTF-8 become U+FFFD
+func (x *Event) Bytes(key string, value []byte) ILogger {
+	if x.ignore {
+		return x
+	}
+	x.buf = appendString(appendKey(append(x.buf, ','), key), string(cut(value)))
+	return x.cutLen(key, value)
+}
+
+func cut(value []byte) []byte {
+	if len(value) > BYTES_MAX {
+		return value[:BYTES_MAX]
+	}
+	return value
+}
+
+func (x *Event) cutLen(key string, value []byte) ILogger {
+	if len(value) > BYTES_MAX {
+		return x.Int(key+"_len", len(value))
+	}
+	return x
+}
+
+// Dur writes d as a number in the configured unit, milliseconds by default
+func (x *Event) Dur(key string, d time.Duration) ILogger {
+	if x.ignore {
+		return x
+	}
+	unit, ok := durationUnits[x.st.config.DurationUnit]
+	if !ok {
+		unit = time.Millisecond
+	}
+	buffer := appendKey(append(x.buf, ','), key)
+	if unit == time.Nanosecond {
+		x.buf = strconv.AppendInt(buffer, int64(d), 10)
+	} else {
+		x.buf = appendFloat(buffer, float64(d)/float64(unit), 64)
+	}
+	return x
+}
+
+// Time writes t in the configured layout, RFC3339 with nanoseconds by default
+func (x *Event) Time(key string, t time.Time) ILogger {
+	if x.ignore {
+		return x
+	}
+	layout := x.st.config.TimeLayout
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	x.buf = appendString(appendKey(append(x.buf, ','), key), t.Format(layout))
+	return x
+}
+
+// TimeDiff writes t - start like Dur, e.g. TimeDiff("elapsed", time.Now(), started)
+func (x *Event) TimeDiff(key string, t, start time.Time) ILogger {
+	return x.Dur(key, t.Sub(start))
+}
+
+// Any writes v marshaled to JSON so structs, maps and slices keep their structure, a value that
+// does not marshal is written as the error text
+func (x *Event) Any(key string, v interface{}) ILogger {
+	if x.ignore {
+		return x
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return x.Str(key, "!marshal: "+err.Error())
+	}
+	return x.RawJSON(key, data)
+}
+
+// RawJSON writes data as is, compacted to keep one entry per line and with the redact patterns
+// applied to its text; invalid JSON and values of redacted or encrypted keys go through Str
+func (x *Event) RawJSON(key string, data []byte) ILogger {
+	if x.ignore {
+		return x
+	}
+	if !json.Valid(data) || redactedKey(x.st, key) || x.st.fieldCiphers[strings.ToLower(key)] != nil {
+		return x.Str(key, string(data))
+	}
+	var compact bytes.Buffer
+	json.Compact(&compact, data)
+	text := redactText(x.st, compact.String())
+	if !json.Valid([]byte(text)) {
+		return x.Str(key, text)
+	}
+	x.buf = append(appendKey(append(x.buf, ','), key), text...)
+	return x
+}
+
+// Strs writes a JSON array, every value is redacted and encrypted like a Str of the same key
+func (x *Event) Strs(key string, values []string) ILogger {
+	if x.ignore {
+		return x
+	}
+	buffer := append(appendKey(append(x.buf, ','), key), '[')
+	for i, value := range values {
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		buffer = appendString(buffer, encryptValue(x.st, key, redactValue(x.st, key, value)))
+	}
+	x.buf = append(buffer, ']')
+	return x
+}
+
+func (x *Event) Ints(key string, values []int) ILogger {
+	if x.ignore {
+		return x
+	}
+	buffer := append(appendKey(append(x.buf, ','), key), '[')
+	for i, value := range values {
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		buffer = strconv.AppendInt(buffer, int64(value), 10)
+	}
+	x.buf = append(buffer, ']')
+	return x
+}
+
+func (x *Event) Floats(key string, values []float64) ILogger {
+	if x.ignore {
+		return x
+	}
+	buffer := append(appendKey(append(x.buf, ','), key), '[')
+	for i, value := range values {
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		buffer = appendFloat(buffer, value, 64)
+	}
+	x.buf = append(buffer, ']')
+	return x
+}
+
+// Errs writes the redacted error texts as an array, nil errors as null
+func (x *Event) Errs(key string, errs []error) ILogger {
+	if x.ignore {
+		return x
+	}
+	buffer := append(appendKey(append(x.buf, ','), key), '[')
+	for i, err := range errs {
+		if i > 0 {
+			buffer = append(buffer, ',')
+		}
+		if err == nil {
+			buffer = append(buffer, "null"...)
+			continue
+		}
+		buffer = appendString(buffer, redactText(x.st, err.Error()))
+	}
+	x.buf = append(buffer, ']')
+	return x
+}
+
+// Dict starts the fields of a nested object for Event.Dict:
+//
+//	log.Info().Dict("geo", log.Dict().Str("country", "US").Int("asn", 13335)).Msg("resolved")
+func Dict() ILogger {
+	return std.Dict()
+}
+
+// Dict starts a nested object redacted and encrypted with the config of this logger
+func (x *Logger) Dict() ILogger {
+	return newEvent(x, x.active(), LOG_TRACE)
+}
+
+// Dict writes the fields of d, made with Dict, as a nested object; d must not be used afterwards
+func (x *Event) Dict(key string, d ILogger) ILogger {
+	dict, ok := d.(*Event)
+	if !ok || dict.ignore {
+		return x
+	}
+	defer dict.release()
+	if x.ignore {
+		return x
+	}
+	buffer := append(appendKey(append(x.buf, ','), key), '{')
+	if len(dict.buf) > 0 {
+		buffer = append(buffer, dict.buf[1:]...)
+	}
+	x.buf = append(buffer, '}')
+	return x
+}
@@ -0,0 +1,52 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+// FlagSet is satisfied by both *flag.FlagSet and *pflag.FlagSet
+type FlagSet interface {
+	StringVar(p *string, name string, value string, usage string)
+}
+
+// Flags holds the values of the standard logging flags after the FlagSet is parsed
+type Flags struct {
+	Level  string
+	File   string
+	Format string
+}
+
+// BindFlags registers --log-level, --log-file and --log-format on fs
+func BindFlags(fs FlagSet) *Flags {
+	x := &Flags{}
+	fs.StringVar(&x.Level, "log-level", "", "log level: trace, debug, info, warn, error or fatal")
+	fs.StringVar(&x.File, "log-file", "", "also write logs to this file")
+	fs.StringVar(&x.Format, "log-format", "", "log format: json or console")
+	return x
+}
+
+// Config builds on the SLOAN_* environment, any flag given on the command line wins
+func (x *Flags) Config() (Config, error) {
+	cfg, err := ConfigFromEnv(Config{})
+	if err != nil {
+		return cfg, err
+	}
+	if x.Level != "" {
+		cfg.Level = x.Level
+	}
+	if x.Format != "" {
+		cfg.Format = x.Format
+	}
+	if x.File != "" {
+		if len(cfg.Sinks) == 0 {
+			cfg.Sinks = []SinkConfig{{Type: "stderr"}}
+		}
+		cfg.Sinks = append(cfg.Sinks, SinkConfig{Type: "file", Path: x.File})
+	}
+	return cfg, nil
+}
+
+func (x *Flags) Init() error {
+	cfg, err := x.Config()
+	if err != nil {
+		return err
+	}
+	return Init(cfg)
+}
@@ -0,0 +1,62 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"os"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var goroutineHeader = regexp.MustCompile(`^goroutine (\d+) \[([^\]]*)\]:`)
+
+// DumpGoroutines writes one error entry per goroutine with its stack, every entry of one dump
+// carries the same "dump" field, and returns how many goroutines were written
+func DumpGoroutines() int {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	dump := strconv.FormatInt(time.Now().UnixNano(), 36)
+	blocks := strings.Split(strings.TrimSpace(string(buf)), "\n\n")
+	for _, block := range blocks {
+		x := Error().Str("dump", dump).Int("goroutines", len(blocks)).(*Event)
+		if match := goroutineHeader.FindStringSubmatch(block); match != nil {
+			x.Str("goroutine", match[1]).Str("state", match[2])
+		}
+		x.RawJSON("stack", marshal(block))
+		x.Msg("goroutine dump")
+	}
+	return len(blocks)
+}
+
+// DumpOnSignal replaces the default SIGQUIT crash with DumpGoroutines and keeps running,
+// call the returned func to restore the default
+func DumpOnSignal() func() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGQUIT)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				DumpGoroutines()
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
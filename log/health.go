@@ -0,0 +1,88 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthReport is the state of the logging pipeline, OK is false when any sink is failing
+type HealthReport struct {
+	OK    bool         `json:"ok"`
+	Sinks []SinkHealth `json:"sinks"`
+}
+
+// SinkHealth reports one sink: Connected is false once a file was closed by a failed rotation,
+// Failing is true until a write succeeds after LastError, Backlog counts entries queued by the sink
+type SinkHealth struct {
+	Name      string     `json:"name"`
+	Connected bool       `json:"connected"`
+	Failing   bool       `json:"failing"`
+	LastError string     `json:"last_error,omitempty"`
+	ErrorAt   *time.Time `json:"error_at,omitempty"`
+	Backlog   int        `json:"backlog"`
+	FlushedAt *time.Time `json:"flushed_at,omitempty"`
+}
+
+// backlogger is implemented by sinks that queue entries
+type backlogger interface {
+	Backlog() int
+}
+
+// Health reports every configured sink, or the InitLogger destinations when no Config was applied,
+// and the writers added with AddWriter
+func Health() HealthReport {
+	x := HealthReport{OK: true, Sinks: []SinkHealth{}}
+	if std.current.Load() == nil {
+		if LOG_STDERR {
+			x.Sinks = append(x.Sinks, SinkHealth{Name: "stderr", Connected: true})
+		}
+		if LOG_FILE != "" {
+			x.Sinks = append(x.Sinks, SinkHealth{Name: LOG_FILE, Connected: LOG_FH != nil})
+			x.OK = LOG_FH != nil
+		}
+	}
+	for _, item := range append(active().all(), std.extra()...) {
+		health := item.health()
+		x.OK = x.OK && health.Connected && !health.Failing
+		x.Sinks = append(x.Sinks, health)
+	}
+	return x
+}
+
+func (x *sink) health() SinkHealth {
+	health := SinkHealth{Name: x.name, Connected: true, Failing: x.failing.Load()}
+	if fh, ok := x.w.(*RotatingFile); ok {
+		health.Connected = !fh.isClosed()
+	}
+	if x.queue != nil {
+		health.Backlog = x.queue.Backlog()
+	} else if queue, ok := x.w.(backlogger); ok {
+		health.Backlog = queue.Backlog()
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.lastError != nil {
+		errorAt := x.errorAt
+		health.LastError = x.lastError.Error()
+		health.ErrorAt = &errorAt
+	}
+	if !x.flushedAt.IsZero() {
+		flushedAt := x.flushedAt
+		health.FlushedAt = &flushedAt
+	}
+	return health
+}
+
+// HealthHandler serves Health as JSON with status 503 when it is not OK, for /healthz endpoints
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := Health()
+		w.Header().Set("Content-Type", "application/json")
+		if !report.OK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
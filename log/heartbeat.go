@@ -0,0 +1,47 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"runtime"
+	"time"
+)
+
+// NOTE uptime is measured from package initialization
+var started = time.Now()
+
+// Heartbeat logs a compact info entry every interval with uptime, goroutines, heap in use and
+// the entries written since the previous beat; call the returned func to stop
+func Heartbeat(interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		last := totalEntries()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			var mem runtime.MemStats
+			runtime.ReadMemStats(&mem)
+			total := totalEntries()
+			Info().Str("component", "heartbeat").
+				Int64("uptime_s", int64(time.Since(started).Seconds())).
+				Int("goroutines", runtime.NumGoroutine()).
+				Int64("heap_bytes", int64(mem.HeapAlloc)).
+				Int64("entries", int64(total-last)).
+				Msg("heartbeat")
+			last = total
+		}
+	}()
+	return func() { close(done) }
+}
+
+func totalEntries() uint64 {
+	total := uint64(0)
+	for _, counter := range metricEntries {
+		total += counter.Load()
+	}
+	return total
+}
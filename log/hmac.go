@@ -0,0 +1,78 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// HMAC_SUFFIX names the file written next to every rotated file of an HMAC sink, see VerifyHMAC
+const HMAC_SUFFIX = ".hmac"
+
+// HMACPath returns the HMAC file of a rotated log file
+func HMACPath(path string) string {
+	return path + HMAC_SUFFIX
+}
+
+type hmacRecord struct {
+	Key  string `json:"key"`
+	HMAC string `json:"hmac"`
+}
+
+// EnableHMAC seals every file on rotation with an HMAC-SHA256 keyed by keyID from the
+// KeyProvider, editing an archived file then needs the deployment secret to go unnoticed
+func (x *RotatingFile) EnableHMAC(keyID string) error {
+	key, err := lookupKey(keyID)
+	if err != nil {
+		return err
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.hmacKey = key
+	x.hmacID = keyID
+	return nil
+}
+
+func fileHMAC(path string, key []byte) (string, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close()
+	mac := hmac.New(sha256.New, key)
+	if _, err := io.Copy(mac, fh); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func writeHMAC(path, keyID, sum string) error {
+	data, _ := json.Marshal(hmacRecord{Key: keyID, HMAC: sum})
+	return os.WriteFile(HMACPath(path), append(data, '\n'), 0400)
+}
+
+// VerifyHMAC recomputes the HMAC of a rotated file with the key named in its HMAC file
+func VerifyHMAC(path string) (bool, error) {
+	data, err := os.ReadFile(HMACPath(path))
+	if err != nil {
+		return false, err
+	}
+	record := hmacRecord{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return false, fmt.Errorf("%s: %w", HMACPath(path), err)
+	}
+	key, err := lookupKey(record.Key)
+	if err != nil {
+		return false, err
+	}
+	sum, err := fileHMAC(path, key)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal([]byte(sum), []byte(record.HMAC)), nil
+}
@@ -0,0 +1,72 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// rotateSealed writes a line to a file sealed with keyID and rotates it, returning the rotated file
+func rotateSealed(t *testing.T, keyID string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "a.log")
+	fh, err := NewRotatingFile(path, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	if err := fh.EnableHMAC(keyID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fh.Write([]byte(`{"message":"one"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fh.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	return backupName(path, 1)
+}
+
+func TestHMACRoundTrip(t *testing.T) {
+	testKeys(t)
+	name := rotateSealed(t, "k1")
+	if ok, err := VerifyHMAC(name); err != nil || !ok {
+		t.Fatalf("sealed file: %v %v", ok, err)
+	}
+}
+
+func TestHMACDetectsTampering(t *testing.T) {
+	cases := map[string]func([]byte) []byte{
+		"modified": func(data []byte) []byte {
+			return append([]byte(`{"message":"two"}`), data[len(`{"message":"one"}`):]...)
+		},
+		"truncated": func(data []byte) []byte { return data[:len(data)-1] },
+		"appended":  func(data []byte) []byte { return append(data, `{"message":"two"}`+"\n"...) },
+	}
+	for name, edit := range cases {
+		t.Run(name, func(t *testing.T) {
+			testKeys(t)
+			rotated := rotateSealed(t, "k1")
+			data, err := os.ReadFile(rotated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(rotated, edit(data), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := VerifyHMAC(rotated); err != nil || ok {
+				t.Fatalf("expected the HMAC to fail: %v %v", ok, err)
+			}
+		})
+	}
+}
+
+func TestHMACNeedsTheKey(t *testing.T) {
+	testKeys(t)
+	rotated := rotateSealed(t, "k1")
+	SetKeyProvider(func(string) ([]byte, error) { return make([]byte, 32), nil })
+	if ok, err := VerifyHMAC(rotated); err != nil || ok {
+		t.Fatalf("expected another key to fail: %v %v", ok, err)
+	}
+}
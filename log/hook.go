@@ -0,0 +1,90 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "encoding/json"
+
+// Hook sees every entry that passed the level and sampling checks just before it is encoded,
+// it can add fields with e.Str and friends or veto the entry with e.Discard; audit entries are
+// written without hooks so none can drop them
+type Hook interface {
+	Run(e *Event, level Level, msg string)
+}
+
+// HookFunc lets a plain function be a Hook
+type HookFunc func(e *Event, level Level, msg string)
+
+func (x HookFunc) Run(e *Event, level Level, msg string) {
+	x(e, level, msg)
+}
+
+// AddHook adds a hook to the default logger
+func AddHook(h Hook) {
+	std.AddHook(h)
+}
+
+// AddHook runs h for the entries of the logger and its children, in the order hooks were added
+func (x *Logger) AddHook(h Hook) {
+	for {
+		old := x.hooks.Load()
+		hooks := []Hook{}
+		if old != nil {
+			hooks = append(hooks, *old...)
+		}
+		hooks = append(hooks, h)
+		if x.hooks.CompareAndSwap(old, &hooks) {
+			return
+		}
+	}
+}
+
+// Discard drops the entry when called from a hook, fatal and panic entries still end the process
+func (x *Event) Discard() {
+	if !x.ignore {
+		x.discard = true
+	}
+}
+
+// Level is the severity of the entry, audit entries report FatalLevel
+func (x *Event) Level() Level {
+	return levelOfEvent(x.level)
+}
+
+// Template is the message before its {key} placeholders were replaced, so entries that differ
+// only in those values group together
+func (x *Event) Template() string {
+	return x.template
+}
+
+// Error is the error last passed to Err, nil when there was none
+func (x *Event) Error() error {
+	return x.err
+}
+
+// Redact masks text like the message and fields of the entry are masked, for hooks that forward
+// the message or error text somewhere else
+func (x *Event) Redact(text string) string {
+	return redactText(x.st, text)
+}
+
+// Fields decodes the fields added so far without the level, for hooks that forward entries
+func (x *Event) Fields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	if len(x.buf) > 0 {
+		json.Unmarshal(append(append([]byte{'{'}, x.buf[1:]...), '}'), &fields)
+	}
+	delete(fields, x.st.config.levelKey())
+	return fields
+}
+
+// runHooks reports whether the entry is still to be written
+func (x *Event) runHooks(msg string) bool {
+	hooks := x.logger.hooks.Load()
+	if hooks == nil {
+		return true
+	}
+	level := x.Level()
+	for _, h := range *hooks {
+		h.Run(x, level, msg)
+	}
+	return !x.discard
+}
@@ -0,0 +1,12 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+// IfErr returns an error logger with err attached, or a no-op logger when err is nil:
+//
+//	log.IfErr(err).Str("op", "fetch").Msg("failed")
+func IfErr(err error) ILogger {
+	if err == nil {
+		return disabled
+	}
+	return Error().Err(err)
+}
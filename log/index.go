@@ -0,0 +1,133 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// INDEX_EVERY is how many bytes of log a file sink writes between index records
+const INDEX_EVERY = 64 * 1024
+
+// NOTE index records are 16 bytes, big-endian unix nanoseconds then byte offset
+const INDEX_RECORD = 16
+
+type indexRecord struct {
+	time   int64
+	offset int64
+}
+
+func IndexPath(path string) string {
+	return path + ".idx"
+}
+
+func writeIndex(w io.Writer, now time.Time, offset int64) error {
+	var record [INDEX_RECORD]byte
+	binary.BigEndian.PutUint64(record[0:8], uint64(now.UnixNano()))
+	binary.BigEndian.PutUint64(record[8:16], uint64(offset))
+	_, err := w.Write(record[:])
+	return err
+}
+
+func readIndex(path string) ([]indexRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]indexRecord, 0, len(data)/INDEX_RECORD)
+	for i := 0; i+INDEX_RECORD <= len(data); i += INDEX_RECORD {
+		records = append(records, indexRecord{
+			time:   int64(binary.BigEndian.Uint64(data[i : i+8])),
+			offset: int64(binary.BigEndian.Uint64(data[i+8 : i+16])),
+		})
+	}
+	return records, nil
+}
+
+// IndexedReader reads a log file, using its side index when present to skip to a time range
+type IndexedReader struct {
+	fh    *os.File
+	r     io.ReadCloser
+	index []indexRecord
+}
+
+// OpenIndexed falls back to a full scan for encrypted files, which are never indexed
+func OpenIndexed(path string) (*IndexedReader, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if keyID, _ := readHeader(bufio.NewReader(fh)); keyID != "" {
+		fh.Close()
+		r, err := OpenLog(path)
+		if err != nil {
+			return nil, err
+		}
+		return &IndexedReader{r: r}, nil
+	}
+	index, err := readIndex(IndexPath(path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fh.Close()
+		return nil, err
+	}
+	return &IndexedReader{fh: fh, index: index}, nil
+}
+
+// Range positions at the last indexed offset written before since and returns a decoder that
+// skips older entries and stops at until, zero times leave that end open
+func (x *IndexedReader) Range(since, until time.Time) (*RangeDecoder, error) {
+	if x.r != nil {
+		return &RangeDecoder{decoder: NewDecoder(x.r), since: since, until: until}, nil
+	}
+	offset := int64(0)
+	if !since.IsZero() {
+		i := sort.Search(len(x.index), func(i int) bool { return x.index[i].time > since.UnixNano() })
+		if i > 0 {
+			offset = x.index[i-1].offset
+		}
+	}
+	if _, err := x.fh.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &RangeDecoder{decoder: NewDecoder(x.fh), since: since, until: until}, nil
+}
+
+func (x *IndexedReader) Close() error {
+	if x.r != nil {
+		return x.r.Close()
+	}
+	return x.fh.Close()
+}
+
+type RangeDecoder struct {
+	decoder *Decoder
+	since   time.Time
+	until   time.Time
+}
+
+// Decode returns the next entry in range or io.EOF, malformed lines are skipped
+func (x *RangeDecoder) Decode() (*Entry, error) {
+	for {
+		entry, err := x.decoder.Decode()
+		if errors.Is(err, ErrMalformedEntry) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// NOTE entry times are truncated to the second, so compare at that precision
+		stamp := entry.Time
+		if !x.since.IsZero() && stamp.Before(x.since.Truncate(time.Second)) {
+			continue
+		}
+		if !x.until.IsZero() && !stamp.Before(x.until) {
+			return nil, io.EOF
+		}
+		return entry, nil
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const JOURNALD_SOCKET = "/run/systemd/journal/socket"
+
+// NOTE with JOURNALD_AUTO a json stderr sink writes to journald instead when systemd connected
+// stderr to the journal (JOURNAL_STREAM is set), so units get indexed fields without config
+var JOURNALD_AUTO = true
+
+// JournaldWriter sends every entry as one native journal datagram: the message becomes MESSAGE,
+// the level PRIORITY as for syslog and every other field an upper case journal field, e.g.
+// "job_id" is JOB_ID; entries larger than a datagram fail since the fd passing fallback of
+// sd_journal is not implemented
+type JournaldWriter struct {
+	conn    *net.UnixConn
+	tag     string
+	entries Config // the keys the entries are written with
+}
+
+func NewJournaldWriter() (*JournaldWriter, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: JOURNALD_SOCKET, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("journald: %w", err)
+	}
+	x := &JournaldWriter{conn: conn}
+	x.tag, _, _ = buildInfo()
+	return x, nil
+}
+
+// journalStream reports whether stderr most likely goes to the journal
+func journalStream() bool {
+	if os.Getenv("JOURNAL_STREAM") == "" {
+		return false
+	}
+	_, err := os.Stat(JOURNALD_SOCKET)
+	return err == nil
+}
+
+func (x *JournaldWriter) Write(p []byte) (int, error) {
+	return x.WriteLevel(InfoLevel, p)
+}
+
+func (x *JournaldWriter) WriteLevel(level Level, p []byte) (int, error) {
+	severity, ok := SYSLOG_SEVERITIES[level]
+	if !ok {
+		severity = 6
+	}
+	buf := appendJournal(nil, "PRIORITY", strconv.Itoa(severity))
+	buf = appendJournal(buf, "SYSLOG_IDENTIFIER", x.tag)
+	keys, values, ok := orderedFields(p)
+	if !ok {
+		buf = appendJournal(buf, "MESSAGE", strings.TrimRight(string(p), "\n"))
+	}
+	for _, key := range keys {
+		value := ""
+		if json.Unmarshal(values[key], &value) != nil {
+			value = string(values[key])
+		}
+		switch key {
+		case x.entries.messageKey():
+			buf = appendJournal(buf, "MESSAGE", value)
+		case x.entries.timeKey(), x.entries.levelKey():
+		default:
+			buf = appendJournal(buf, journalKey(key), value)
+		}
+	}
+	if _, err := x.conn.Write(buf); err != nil {
+		return 0, fmt.Errorf("journald: %w", err)
+	}
+	return len(p), nil
+}
+
+// journalKey makes a valid journal field name: upper case letters, digits and underscores, not
+// starting with an underscore or digit since those are reserved or invalid
+func journalKey(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+	name = strings.TrimLeft(name, "_0123456789")
+	if name == "" || name == "MESSAGE" || name == "PRIORITY" || name == "SYSLOG_IDENTIFIER" {
+		name = "FIELD_" + name
+	}
+	return name
+}
+
+// appendJournal writes KEY=value, or the length prefixed form when value has a newline
+func appendJournal(dst []byte, key, value string) []byte {
+	if !strings.Contains(value, "\n") {
+		return append(append(append(append(dst, key...), '='), value...), '\n')
+	}
+	dst = append(append(dst, key...), '\n')
+	dst = binary.LittleEndian.AppendUint64(dst, uint64(len(value)))
+	return append(append(dst, value...), '\n')
+}
+
+func (x *JournaldWriter) Close() error {
+	return x.conn.Close()
+}
@@ -0,0 +1,143 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Level orders the severities, a logger at a level writes it and every level above it; the
+// LOG_* masks stay the internal representation
+type Level int8
+
+const (
+	TraceLevel Level = iota
+	DebugLevel
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	FatalLevel
+)
+
+var levelNames = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+// ParseLevel accepts the level names in any case
+func ParseLevel(name string) (Level, error) {
+	for i, item := range levelNames {
+		if strings.EqualFold(name, item) {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, use trace, debug, info, warn, error or fatal", name)
+}
+
+func (x Level) String() string {
+	if x < TraceLevel || x > FatalLevel {
+		return fmt.Sprintf("Level(%d)", int8(x))
+	}
+	return levelNames[x]
+}
+
+// mask is the LOG_LEVEL value that writes x and above
+func (x Level) mask() int {
+	switch x {
+	case TraceLevel:
+		return LOG_TRACE
+	case DebugLevel:
+		return LOG_ERROR | LOG_WARN | LOG_INFO | LOG_DEBUG
+	case InfoLevel:
+		return LOG_ERROR | LOG_WARN | LOG_INFO
+	case WarnLevel:
+		return LOG_ERROR | LOG_WARN
+	case ErrorLevel:
+		return LOG_ERROR
+	}
+	return LOG_FATAL
+}
+
+// levelOfEvent maps the LOG_* bit of an event to its Level
+func levelOfEvent(level int) Level {
+	result, _ := ParseLevel(eventName(level, false))
+	return result
+}
+
+// levelFromMask maps a LOG_LEVEL mask back to the lowest level it writes
+func levelFromMask(mask int) Level {
+	for level := TraceLevel; level < FatalLevel; level++ {
+		if level.mask() == mask {
+			return level
+		}
+	}
+	return FatalLevel
+}
+
+// SetLevel changes the level of the default logger on the fly, see Logger.SetLevel
+func SetLevel(level Level) {
+	std.SetLevel(level)
+}
+
+// GetLevel returns the level of the default logger
+func GetLevel() Level {
+	return std.GetLevel()
+}
+
+// SetLevel changes the level of the logger and its children without a restart, it holds until
+// the next Apply; component levels are kept, on a Component logger only that component changes
+func (x *Logger) SetLevel(level Level) {
+	if x.component != "" {
+		x.setComponentLevel(x.component, level.mask())
+		return
+	}
+	x.level.Store(int32(level.mask()))
+	if x.legacy {
+		LOG_LEVEL = level.mask()
+	}
+}
+
+func (x *Logger) GetLevel() Level {
+	if x.component != "" {
+		return levelFromMask(x.componentMask(x.active(), x.component))
+	}
+	return levelFromMask(x.mask())
+}
+
+// LevelHandler serves the level of the default logger as {"level":"info"} on GET and changes it
+// on PUT with the same body, e.g. mounted at /-/loglevel
+func LevelHandler() http.Handler {
+	return std.LevelHandler()
+}
+
+func (x *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			body := struct {
+				Level string `json:"level"`
+			}{}
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			level, err := ParseLevel(body.Level)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			old := x.GetLevel()
+			x.SetLevel(level)
+			x.Info().Str("component", "osintami").Str("from", old.String()).Str("to", level.String()).Str("remote", r.RemoteAddr).Msg("log level changed")
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			json.NewEncoder(w).Encode(map[string]string{"error": "use GET or PUT"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"level": x.GetLevel().String()})
+	})
+}
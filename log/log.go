@@ -3,16 +3,23 @@
 package log
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// NOTE LOG_LEVEL is a mask of the levels written, LOG_TRACE is every bit so trace events only
+// pass a trace mask, see Level for the ordered names
 const (
 	LOG_TRACE = 0xFF
 	LOG_FATAL = 0x00
+	LOG_DEBUG = 0x08
 	LOG_INFO  = 0x04
 	LOG_WARN  = 0x02
 	LOG_ERROR = 0x01
@@ -23,38 +30,69 @@ type ILogger interface {
 	Int(string, int) ILogger
 	Int64(string, int64) ILogger
 	Float(string, float32) ILogger
+	Uint64(string, uint64) ILogger
+	Float64(string, float64) ILogger
+	Hex(string, []byte) ILogger
+	Bytes(string, []byte) ILogger
 	Bool(string, bool) ILogger
+	Dur(string, time.Duration) ILogger
+	Time(string, time.Time) ILogger
+	TimeDiff(string, time.Time, time.Time) ILogger
+	Any(string, interface{}) ILogger
+	Strs(string, []string) ILogger
+	Ints(string, []int) ILogger
+	Floats(string, []float64) ILogger
+	Errs(string, []error) ILogger
+	Dict(string, ILogger) ILogger
+	Caller(...int) ILogger
+	RawJSON(string, []byte) ILogger
 	Err(error) ILogger
+	Start() ILogger
+	End(string)
 	Msg(string)
+	Msgf(string, ...interface{})
+	Send()
 }
 
-type Logger struct {
-	parts  []string
-	ignore bool
+type Event struct {
+	logger    *Logger
+	st        *state
+	buf       []byte // the encoded fields, each starting with ','
+	level     int
+	component string
+	ignore    bool
+	audit     bool
+	start     time.Time
+	trace     *TraceBuffer
+	bare      bool   // written by Send, without a message key
+	caller    bool   // annotated by Caller
+	summary   bool   // counts rate limited entries, never sampled itself
+	discard   bool   // vetoed by a hook
+	template  string // the message before rendering, for hooks
+	err       error  // the last Err, for hooks
+	terminate string
+	// depth counts the helpers between the call site and Msg for the caller annotation
+	depth int
 }
 
 // NOTE global logging variables
 var LOG_FH *os.File
 var LOG_FILE string
-var LOG_LEVEL int = LOG_ERROR
+var LOG_LEVEL int = LOG_ERROR // mirrors the default logger, change it with SetLevel
 var LOG_STDERR bool = true
 
+// NOTE sequence numbers order entries logged within the same second, see Config.Sequence
+var sequence atomic.Uint64
+
 func InitLogger(path, file, level string, standardError bool) {
 
+	legacyMu.Lock()
 	LOG_FILE = fmt.Sprintf("%s/%s", path, file)
 	LOG_STDERR = standardError
 
-	switch strings.ToLower(level) {
-	case "trace":
-		LOG_LEVEL = LOG_TRACE
-	case "debug":
-		LOG_LEVEL = LOG_TRACE
-	case "error":
-		LOG_LEVEL = LOG_ERROR
-	case "warn":
-		LOG_LEVEL = LOG_ERROR | LOG_WARN
-	case "info":
-		LOG_LEVEL = LOG_ERROR | LOG_WARN | LOG_INFO
+	if mask, ok := parseLevel(level); ok {
+		LOG_LEVEL = mask
+		std.level.Store(int32(mask))
 	}
 
 	if file != "" {
@@ -64,148 +102,311 @@ func InitLogger(path, file, level string, standardError bool) {
 		if _, err := os.Stat(LOG_FILE); err != nil {
 			LOG_FH, err = os.Create(LOG_FILE)
 			if err != nil {
-				fmt.Println("[ERROR] create log file failed", err)
+				diagnose("create", LOG_FILE, err)
 			}
 		} else {
 			LOG_FH, err = os.OpenFile(LOG_FILE, os.O_RDWR|os.O_APPEND, 0700)
 			if err != nil {
-				fmt.Println("[ERROR] open log file failed", err)
+				diagnose("open", LOG_FILE, err)
 			}
 		}
 	}
+	legacyMu.Unlock()
 
-	Info().Str("component", "osintami").Str("level", level).Str("file", LOG_FILE).Msg("logging started")
+	Startup()
+}
+
+// InitRotating is InitLogger with size and time based rotation of LOG_FILE, it applies a Config
+// with a file sink, so LOG_FH stays nil
+func InitRotating(path, file, level string, standardError bool, rotation RotationConfig) error {
+	cfg := Config{Level: level, Rotation: rotation, Sinks: []SinkConfig{{Type: "file", Path: filepath.Join(path, file)}}}
+	if standardError {
+		cfg.Sinks = append(cfg.Sinks, SinkConfig{Type: "stderr"})
+	}
+	return Init(cfg)
+}
+
+// parseLevel maps a level name to its LOG_LEVEL bitmask
+func parseLevel(level string) (int, bool) {
+	switch strings.ToLower(level) {
+	case "trace":
+		return LOG_TRACE, true
+	case "debug":
+		return LOG_ERROR | LOG_WARN | LOG_INFO | LOG_DEBUG, true
+	case "error":
+		return LOG_ERROR, true
+	case "warn":
+		return LOG_ERROR | LOG_WARN, true
+	case "info":
+		return LOG_ERROR | LOG_WARN | LOG_INFO, true
+	case "fatal":
+		return LOG_FATAL, true
+	}
+	return 0, false
+}
+
+// levelOf maps a level name to the level an event is logged at
+func levelOf(level string) (int, bool) {
+	switch strings.ToLower(level) {
+	case "trace":
+		return LOG_TRACE, true
+	case "debug":
+		return LOG_DEBUG, true
+	case "info":
+		return LOG_INFO, true
+	case "warn":
+		return LOG_WARN, true
+	case "error":
+		return LOG_ERROR, true
+	case "fatal":
+		return LOG_FATAL, true
+	}
+	return 0, false
 }
 
 func LogFile() string {
 	return LOG_FILE
 }
 
-func NewLogger(level int) *Logger {
-	x := &Logger{parts: []string{}}
-	if LOG_LEVEL&level != level {
-		x.ignore = true
-		return x
-	}
-	return x
+// NewLogger starts an event on the default logger, see Logger for independent instances
+func NewLogger(level int) *Event {
+	return std.event(level)
 }
 
 func Info() ILogger {
-	x := NewLogger(LOG_INFO)
-	if x.ignore {
-		return x
-	}
-	x.parts = append(x.parts, "\"level\":\"info\"")
-	return x
+	return std.Info()
 }
 
 func Warn() ILogger {
-	x := NewLogger(LOG_WARN)
-	if x.ignore {
-		return x
-	}
-	x.parts = append(x.parts, "\"level\":\"warn\"")
-	return x
+	return std.Warn()
 }
 
 func Error() ILogger {
-	x := NewLogger(LOG_ERROR)
-	if x.ignore {
-		return x
-	}
-	x.parts = append(x.parts, "\"level\":\"error\"")
-	return x
+	return std.Error()
 }
 
 func Fatal() ILogger {
-	x := &Logger{parts: []string{}}
-	x.parts = append(x.parts, "\"level\":\"fatal\"")
-	return x
+	return std.Fatal()
 }
 
 func Debug() ILogger {
-	x := NewLogger(LOG_TRACE)
-	if x.ignore {
-		return x
-	}
-	x.parts = append(x.parts, "\"level\":\"debug\"")
-	return x
+	return std.Debug()
 }
 
-// TODO:  preserve stacktrace from one back
-func (x *Logger) Err(err error) ILogger {
+func Trace() ILogger {
+	return std.Trace()
+}
+
+func Panic() ILogger {
+	return std.Panic()
+}
+
+// NOTE EXIT and PANIC end the process after a Fatal or Panic entry, tests can replace them
+var EXIT = os.Exit
+var PANIC = func(msg string) { panic(msg) }
+
+// Err writes the error text and, with Config.ErrorStackTraces, the cause chain and the stack from
+// the caller of Err
+func (x *Event) Err(err error) ILogger {
 	if x.ignore || err == nil {
 		return x
 	}
-	x.parts = append(x.parts, fmt.Sprintf("\"error\":\"%s\"", err.Error()))
+	x.buf = appendString(appendKey(append(x.buf, ','), "error"), redactText(x.st, err.Error()))
+	x.err = err
+	x.errFields(err)
+	if x.st.config.ErrorStackTraces {
+		x.errChain(err)
+		x.errStack(x.st.config.ErrorStackDepth)
+	}
 	return x
 }
 
-func (x *Logger) Str(key, value string) ILogger {
+func (x *Event) Str(key, value string) ILogger {
 	if x.ignore {
 		return x
 	}
-	x.parts = append(x.parts, fmt.Sprintf("\"%s\":\"%s\"", key, value))
+	if key == "component" {
+		x.component = value
+	}
+	x.buf = appendString(appendKey(append(x.buf, ','), key), encryptValue(x.st, key, redactValue(x.st, key, value)))
 	return x
 }
 
-func (x *Logger) Bool(key string, value bool) ILogger {
+func (x *Event) Bool(key string, value bool) ILogger {
 	if x.ignore {
 		return x
 	}
-	x.parts = append(x.parts, fmt.Sprintf("\"%s\":\"%t\"", key, value))
+	x.buf = strconv.AppendBool(appendKey(append(x.buf, ','), key), value)
 	return x
 }
 
-func (x *Logger) Int(key string, value int) ILogger {
+func (x *Event) Int(key string, value int) ILogger {
 	if x.ignore {
 		return x
 	}
-	x.parts = append(x.parts, fmt.Sprintf("\"%s\":\"%d\"", key, value))
+	x.buf = strconv.AppendInt(appendKey(append(x.buf, ','), key), int64(value), 10)
 	return x
 }
 
-func (x *Logger) Int64(key string, value int64) ILogger {
+func (x *Event) Int64(key string, value int64) ILogger {
 	if x.ignore {
 		return x
 	}
-	x.parts = append(x.parts, fmt.Sprintf("\"%s\":\"%d\"", key, value))
+	x.buf = strconv.AppendInt(appendKey(append(x.buf, ','), key), value, 10)
 	return x
 }
 
-func (x *Logger) Float(key string, value float32) ILogger {
+func (x *Event) Float(key string, value float32) ILogger {
 	if x.ignore {
 		return x
 	}
-	x.parts = append(x.parts, fmt.Sprintf("\"%s\":\"%f\"", key, value))
+	x.buf = appendFloat(appendKey(append(x.buf, ','), key), float64(value), 32)
 	return x
 }
 
-func (x *Logger) Msg(msg string) {
+// Msg writes the entry, {key} placeholders in msg are replaced by the value of that field
+func (x *Event) Msg(msg string) {
+	if x.ignore {
+		return
+	}
+	defer x.release()
+	template := msg
+	msg = x.render(msg)
+	st := x.st
+	if x.audit {
+		x.writeAudit(st, x.encode(st, msg))
+		return
+	}
+	comp := st.components[x.component]
+	if !x.logger.enabled(st, x.component, x.level) {
+		if x.trace != nil && (x.level == LOG_DEBUG || x.level == LOG_TRACE) {
+			x.trace.push(x.encode(st, msg))
+		}
+		return
+	}
+	if !x.sampled(comp.sampler(st), template) {
+		metricSampled.Add(1)
+		return
+	}
+	x.template = template
+	if !x.runHooks(msg) {
+		if x.terminate != "" {
+			x.finish(msg)
+		}
+		return
+	}
+
+	out := x.encode(st, msg)
+	if x.trace != nil && x.level <= LOG_ERROR {
+		for _, line := range x.trace.drain() {
+			metricEntries["debug"].Add(1)
+			metricBytes.Add(uint64(len(line)))
+			x.route(st, comp, line)
+		}
+	}
+	if st.dedup.hold(x, comp, msg, out) {
+		return
+	}
+	countEntry(x, out)
+	x.route(st, comp, out)
+	if x.terminate != "" {
+		x.finish(msg)
+	}
+}
+
+// finish flushes what Fatal and Panic entries leave queued before the process goes down
+func (x *Event) finish(msg string) {
+	if err := x.logger.Flush(); err != nil {
+		diagnose("flush", "", err)
+	}
+	if x.terminate == "panic" {
+		PANIC(msg)
+		return
+	}
+	EXIT(1)
+}
+
+// Msgf writes the entry with a fmt.Sprintf message, {key} placeholders are replaced afterwards
+func (x *Event) Msgf(format string, args ...interface{}) {
 	if x.ignore {
 		return
 	}
+	x.depth++
+	x.Msg(fmt.Sprintf(format, args...))
+}
 
-	var buffer bytes.Buffer
-	buffer.Write([]byte("{"))
-	buffer.Write([]byte(fmt.Sprintf("\"time\":\"%s\",", time.Now().Format(time.RFC3339))))
-	for _, item := range x.parts {
-		buffer.Write([]byte(item))
-		buffer.Write([]byte(","))
+// Send writes the entry with its fields only
+func (x *Event) Send() {
+	if x.ignore {
+		return
+	}
+	x.bare = true
+	x.depth++
+	x.Msg("")
+}
+
+// route writes a line everywhere an entry at this logger's level and component goes
+func (x *Event) route(st *state, comp *component, out []byte) {
+	if x.logger.legacy {
+		writeLegacy(out)
+	}
+	writeSinks(st.sinks, x.level, out)
+	writeSinks(x.logger.extra(), x.level, out)
+	if comp != nil {
+		writeSinks(comp.sinks, x.level, out)
 	}
-	buffer.Write([]byte(fmt.Sprintf("\"message\":\"%s\"", msg)))
-	buffer.Write([]byte("}"))
-	buffer.Write([]byte("\n"))
+	publish(out)
+}
 
-	out := buffer.Bytes()
+// NOTE every entry is one complete line handed to each destination in a single Write under a
+// lock: legacyMu for stderr and LOG_FH, the RotatingFile and writer sink locks for the others,
+// so concurrent goroutines never interleave bytes; LOG_FH must only be replaced under legacyMu
+var legacyMu sync.Mutex
+
+// writeLegacy writes to the destinations set up by InitLogger
+func writeLegacy(out []byte) {
+	legacyMu.Lock()
+	defer legacyMu.Unlock()
 	if LOG_STDERR {
-		os.Stderr.Write(out)
+		if _, err := os.Stderr.Write(out); err != nil {
+			countSinkError("stderr")
+		} else {
+			metricStderrBytes.Add(uint64(len(out)))
+		}
 	}
 	if LOG_FH != nil {
-		LOG_FH.Write(out)
+		if _, err := LOG_FH.Write(out); err != nil {
+			countSinkError(LOG_FILE)
+		} else {
+			metricFileBytes.Add(uint64(len(out)))
+		}
+	}
+}
+
+func (x *Event) encode(st *state, msg string) []byte {
+	// NOTE the line is the one allocation of an event, sinks, queues and subscribers keep it
+	buffer := make([]byte, 0, len(x.buf)+len(msg)+96)
+	buffer = appendTimestamp(appendKey(append(buffer, '{'), st.config.timeKey()), st.config, time.Now())
+	buffer = append(buffer, x.buf...)
+	if st.config.Sequence {
+		buffer = strconv.AppendUint(appendKey(append(buffer, ','), "seq"), sequence.Add(1), 10)
+	}
+	if st.config.Caller && !x.caller {
+		buffer = appendCaller(buffer, st.config, 3+x.depth+st.config.CallerSkip)
 	}
+	if !x.bare {
+		// NOTE fields are masked as they are added, the message is only known here
+		buffer = appendString(appendKey(append(buffer, ','), st.config.messageKey()), redactText(st, msg))
+	}
+	return append(buffer, '}', '\n')
 }
 
 func Shutdown() {
 	LOG_FH.Close()
+	std.Close(context.Background())
+}
+
+// Close drains the async queues of the default logger until ctx is done and closes its sinks
+func Close(ctx context.Context) error {
+	return std.Close(ctx)
 }
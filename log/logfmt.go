@@ -0,0 +1,65 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LogfmtWriter rewrites each entry as `time=... level=info msg="..." key=value` for drains that
+// prefer logfmt, e.g. log.AddWriter(&log.LogfmtWriter{Out: conn}); lines that are not JSON are
+// written as they are
+type LogfmtWriter struct {
+	Out io.Writer
+}
+
+func (x *LogfmtWriter) Write(p []byte) (int, error) {
+	if _, err := x.Out.Write(logfmt(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// logfmt keeps the field order of the entry, message is written as msg
+func logfmt(line []byte) []byte {
+	keys, values, ok := orderedFields(line)
+	if !ok {
+		return line
+	}
+	buf := []byte{}
+	for i, key := range keys {
+		if i > 0 {
+			buf = append(buf, ' ')
+		}
+		name := key
+		if key == "message" {
+			name = "msg"
+		}
+		buf = append(append(append(buf, logfmtKey(name)...), '='), logfmtValue(values[key])...)
+	}
+	return append(buf, '\n')
+}
+
+// logfmtKey replaces what would end the key early
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue writes strings bare unless they need quotes, objects and arrays as quoted JSON
+func logfmtValue(value json.RawMessage) string {
+	text := ""
+	if json.Unmarshal(value, &text) != nil {
+		text = string(value)
+	}
+	if text == "" || strings.ContainsAny(text, " \t\r\n\"=\\") {
+		return strconv.Quote(text)
+	}
+	return text
+}
@@ -0,0 +1,126 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Logger owns its sinks, level and options so one process can run several, e.g. an application
+// log and an audit log with different destinations; the package level functions use the
+// default Logger, the only one that honors LOG_LEVEL and writes to the InitLogger destinations
+type Logger struct {
+	// NOTE children made by With share the state of their root so a reload reaches all of them
+	current   *atomic.Pointer[state]
+	writers   *atomic.Pointer[[]*sink]
+	hooks     *atomic.Pointer[[]Hook]
+	level     *atomic.Int32                    // the LOG_* mask, set by Apply and SetLevel
+	overrides *atomic.Pointer[componentLevels] // set by SetLevel on component loggers
+	legacy    bool
+	fields    []byte   // encoded once by With, each field starting with ','
+	sample    *sampler // set by Sample and RateLimit
+	component string
+}
+
+var std = &Logger{current: &atomic.Pointer[state]{}, writers: &atomic.Pointer[[]*sink]{}, hooks: &atomic.Pointer[[]Hook]{}, level: newLevel(LOG_ERROR), overrides: &atomic.Pointer[componentLevels]{}, legacy: true}
+
+// Default returns the logger behind the package level functions
+func Default() *Logger {
+	return std
+}
+
+// New builds an independent logger from cfg, Close it when done
+func New(cfg Config) (*Logger, error) {
+	x := &Logger{current: &atomic.Pointer[state]{}, writers: &atomic.Pointer[[]*sink]{}, hooks: &atomic.Pointer[[]Hook]{}, level: newLevel(LOG_ERROR), overrides: &atomic.Pointer[componentLevels]{}}
+	if err := x.Apply(cfg); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *Logger) active() *state {
+	if st := x.current.Load(); st != nil {
+		return st
+	}
+	return empty
+}
+
+func newLevel(mask int) *atomic.Int32 {
+	x := &atomic.Int32{}
+	x.Store(int32(mask))
+	return x
+}
+
+// mask is the level events are checked against, components may override it
+func (x *Logger) mask() int {
+	return int(x.level.Load())
+}
+
+func (x *Logger) event(level int) *Event {
+	st := x.active()
+	if (x.mask()|x.floor(st))&level != level {
+		return disabled
+	}
+	return newEvent(x, st, level)
+}
+
+func (x *Logger) Info() ILogger {
+	return x.leveled(LOG_INFO, "info")
+}
+
+func (x *Logger) Warn() ILogger {
+	return x.leveled(LOG_WARN, "warn")
+}
+
+func (x *Logger) Error() ILogger {
+	return x.leveled(LOG_ERROR, "error")
+}
+
+func (x *Logger) Debug() ILogger {
+	return x.leveled(LOG_DEBUG, "debug")
+}
+
+// Trace is below Debug, for detail only wanted while chasing a problem
+func (x *Logger) Trace() ILogger {
+	return x.leveled(LOG_TRACE, "trace")
+}
+
+// Fatal is never filtered by level, Msg flushes the logger and calls EXIT(1) after writing
+func (x *Logger) Fatal() ILogger {
+	e := newEvent(x, x.active(), LOG_FATAL)
+	e.appendLevel("fatal")
+	e.buf = append(append(e.buf, e.st.static...), x.fields...)
+	e.terminate = "fatal"
+	return e
+}
+
+// Panic is never filtered by level, Msg flushes the logger and calls PANIC with the message
+func (x *Logger) Panic() ILogger {
+	e := newEvent(x, x.active(), LOG_FATAL)
+	e.appendLevel("panic")
+	e.buf = append(append(e.buf, e.st.static...), x.fields...)
+	e.terminate = "panic"
+	return e
+}
+
+func (x *Logger) leveled(level int, name string) ILogger {
+	e := x.event(level)
+	if e.ignore {
+		return e
+	}
+	e.appendLevel(name)
+	e.buf = append(append(e.buf, e.st.static...), x.fields...)
+	return e
+}
+
+// Close writes what the async queues hold until ctx is done, then closes every sink of the
+// logger and its children, later events are dropped
+func (x *Logger) Close(ctx context.Context) error {
+	// NOTE the suppressed counts are entries of the logger, so before it stops taking entries
+	x.sample.flush()
+	x.active().summarize()
+	st := x.current.Swap(nil)
+	err := st.drain(ctx)
+	st.close()
+	return err
+}
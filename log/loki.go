@@ -0,0 +1,253 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NOTE a LokiWriter pushes when LOKI_BATCH lines are pending or the oldest has waited
+// LOKI_WAIT, holds up to LOKI_BUFFER lines meanwhile and retries a failed push LOKI_RETRIES
+// times with the NetWriter backoff before the batch is dropped
+const (
+	LOKI_BATCH   = 1000
+	LOKI_WAIT    = time.Second
+	LOKI_BUFFER  = 8192
+	LOKI_RETRIES = 5
+	LOKI_PUSH    = "/loki/api/v1/push"
+)
+
+var lokiLabel = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// LokiConfig applies to sinks of type "loki", whose Path is the Loki URL, e.g. http://loki:3100;
+// every stream is labeled service (SERVICE when not in Labels) and level, add env and others
+// with Labels; Tenant is sent as X-Scope-OrgID for multi-tenant Loki
+type LokiConfig struct {
+	Labels    map[string]string `json:"labels" yaml:"labels" toml:"labels"`
+	BatchSize int               `json:"batch_size" yaml:"batch_size" toml:"batch_size"`
+	BatchWait string            `json:"batch_wait" yaml:"batch_wait" toml:"batch_wait"`
+	Tenant    string            `json:"tenant" yaml:"tenant" toml:"tenant"`
+}
+
+type lokiEntry struct {
+	level Level
+	at    time.Time
+	line  []byte
+}
+
+// LokiWriter pushes entries to the Loki HTTP API from a background goroutine, one stream per
+// level, so Write never waits on the network
+type LokiWriter struct {
+	url       string
+	labels    map[string]string
+	tenant    string
+	batch     int
+	wait      time.Duration
+	client    *http.Client
+	ch        chan lokiEntry
+	flush     chan chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	failing   atomic.Bool
+}
+
+func NewLokiWriter(address string, cfg LokiConfig) (*LokiWriter, error) {
+	target, err := url.Parse(address)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("loki: address %q is not an http:// or https:// URL", address)
+	}
+	if target.Path == "" || target.Path == "/" {
+		target.Path = LOKI_PUSH
+	}
+	x := &LokiWriter{url: target.String(), labels: map[string]string{}, tenant: cfg.Tenant, batch: cfg.BatchSize, wait: LOKI_WAIT,
+		client: &http.Client{Timeout: NET_DIAL_TIMEOUT * 2}, ch: make(chan lokiEntry, LOKI_BUFFER), flush: make(chan chan struct{}), done: make(chan struct{}), stopped: make(chan struct{})}
+	if x.batch <= 0 {
+		x.batch = LOKI_BATCH
+	}
+	if cfg.BatchWait != "" {
+		if x.wait, err = time.ParseDuration(cfg.BatchWait); err != nil || x.wait <= 0 {
+			return nil, fmt.Errorf("loki: batch_wait %q is not a positive duration", cfg.BatchWait)
+		}
+	}
+	x.labels["service"], _, _ = buildInfo()
+	for key, value := range cfg.Labels {
+		if !lokiLabel.MatchString(key) || key == "level" {
+			return nil, fmt.Errorf("loki: invalid label name %q", key)
+		}
+		x.labels[key] = value
+	}
+	go x.run()
+	return x, nil
+}
+
+func (x *LokiWriter) Write(p []byte) (int, error) {
+	return x.WriteLevel(InfoLevel, p)
+}
+
+// WriteLevel queues the line in the stream of its level, a full buffer drops it
+func (x *LokiWriter) WriteLevel(level Level, p []byte) (int, error) {
+	select {
+	case <-x.done:
+		return 0, errors.New("loki writer closed")
+	default:
+	}
+	select {
+	case x.ch <- lokiEntry{level: level, at: time.Now(), line: bytes.TrimRight(append([]byte{}, p...), "\n")}:
+	default:
+		metricDropped.Add(1)
+	}
+	return len(p), nil
+}
+
+func (x *LokiWriter) run() {
+	defer close(x.stopped)
+	pending := []lokiEntry{}
+	timer := time.NewTimer(x.wait)
+	timer.Stop()
+	for {
+		select {
+		case item := <-x.ch:
+			if len(pending) == 0 {
+				timer.Reset(x.wait)
+			}
+			if pending = append(pending, item); len(pending) >= x.batch {
+				timer.Stop()
+				x.push(pending, true)
+				pending = pending[:0]
+			}
+		case <-timer.C:
+			x.push(pending, true)
+			pending = pending[:0]
+		case reply := <-x.flush:
+			timer.Stop()
+			pending = x.drain(pending)
+			close(reply)
+		case <-x.done:
+			timer.Stop()
+			x.drain(pending)
+			return
+		}
+	}
+}
+
+// drain pushes pending and everything queued without retrying, for Flush and Close
+func (x *LokiWriter) drain(pending []lokiEntry) []lokiEntry {
+	for len(x.ch) > 0 {
+		pending = append(pending, <-x.ch)
+	}
+	for len(pending) > 0 {
+		n := min(len(pending), x.batch)
+		x.push(pending[:n], false)
+		pending = pending[n:]
+	}
+	return pending[:0]
+}
+
+// payload groups the entries into one stream per level in the push API format
+func (x *LokiWriter) payload(entries []lokiEntry) []byte {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	streams, index := []*stream{}, map[Level]*stream{}
+	for _, item := range entries {
+		s, ok := index[item.level]
+		if !ok {
+			labels := map[string]string{"level": item.level.String()}
+			for key, value := range x.labels {
+				labels[key] = value
+			}
+			s = &stream{Stream: labels}
+			index[item.level] = s
+			streams = append(streams, s)
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(item.at.UnixNano(), 10), string(item.line)})
+	}
+	data, _ := json.Marshal(map[string][]*stream{"streams": streams})
+	return data
+}
+
+// push retries network errors, 429 and 5xx responses with backoff while retry is set, other
+// rejections are not retried since Loki would refuse the batch again
+func (x *LokiWriter) push(entries []lokiEntry, retry bool) {
+	if len(entries) == 0 {
+		return
+	}
+	body := x.payload(entries)
+	backoff := NET_BACKOFF_MIN
+	for attempt := 0; ; attempt++ {
+		again, err := x.post(body)
+		if err == nil {
+			if x.failing.Swap(false) {
+				diagnose("recover", x.url, nil)
+			}
+			return
+		}
+		if !x.failing.Swap(true) {
+			diagnose("push", x.url, err)
+		}
+		if !again || !retry || attempt == LOKI_RETRIES {
+			metricDropped.Add(uint64(len(entries)))
+			return
+		}
+		select {
+		case <-x.done:
+			retry = false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, NET_BACKOFF_MAX)
+	}
+}
+
+func (x *LokiWriter) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, x.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if x.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", x.tenant)
+	}
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("unexpected status %s", resp.Status)
+	if text, _ := io.ReadAll(io.LimitReader(resp.Body, 512)); len(bytes.TrimSpace(text)) > 0 {
+		err = fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(text))
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// Flush pushes what is pending before ctx is done, Logger.Flush calls it so a Fatal entry and
+// those before it reach Loki before EXIT
+func (x *LokiWriter) Flush(ctx context.Context) error {
+	return flushLoop(ctx, x.flush, x.stopped, "loki")
+}
+
+// Close pushes what is pending, waiting up to NET_CLOSE_TIMEOUT
+func (x *LokiWriter) Close() error {
+	x.closeOnce.Do(func() { close(x.done) })
+	select {
+	case <-x.stopped:
+		return nil
+	case <-time.After(NET_CLOSE_TIMEOUT):
+		return errors.New("loki writer: timed out pushing pending entries")
+	}
+}
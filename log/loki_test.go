@@ -0,0 +1,138 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pushed is a request received by a pushServer
+type pushed struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// pushServer records the requests it receives and answers them with statuses in order, then 200
+func pushServer(t *testing.T, statuses ...int) (*httptest.Server, <-chan pushed) {
+	t.Helper()
+	var mu sync.Mutex
+	requests := make(chan pushed, 64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		status := http.StatusOK
+		if len(statuses) > 0 {
+			status, statuses = statuses[0], statuses[1:]
+		}
+		mu.Unlock()
+		if status == http.StatusOK {
+			requests <- pushed{path: r.URL.Path, header: r.Header, body: body}
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// push waits up to two seconds for the next request
+func push(t *testing.T, requests <-chan pushed) pushed {
+	t.Helper()
+	select {
+	case item := <-requests:
+		return item
+	case <-time.After(2 * time.Second):
+		t.Fatal("nothing was pushed")
+	}
+	return pushed{}
+}
+
+type lokiPush struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+func TestLokiBatchesStreamsPerLevel(t *testing.T) {
+	server, requests := pushServer(t)
+	w, err := NewLokiWriter(server.URL, LokiConfig{Labels: map[string]string{"env": "test"}, Tenant: "team", BatchSize: 3, BatchWait: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.WriteLevel(InfoLevel, []byte(`{"message":"one"}`+"\n"))
+	w.WriteLevel(ErrorLevel, []byte(`{"message":"two"}`+"\n"))
+	w.WriteLevel(InfoLevel, []byte(`{"message":"three"}`+"\n"))
+
+	item := push(t, requests)
+	if item.path != LOKI_PUSH || item.header.Get("X-Scope-OrgID") != "team" {
+		t.Fatalf("push to %s with tenant %q", item.path, item.header.Get("X-Scope-OrgID"))
+	}
+	body := lokiPush{}
+	if err := json.Unmarshal(item.body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Streams) != 2 {
+		t.Fatalf("expected a stream per level: %s", item.body)
+	}
+	info := body.Streams[0]
+	if info.Stream["level"] != "info" || info.Stream["env"] != "test" || info.Stream["service"] == "" || len(info.Values) != 2 {
+		t.Fatalf("info stream: %s", item.body)
+	}
+	if info.Values[1][1] != `{"message":"three"}` {
+		t.Fatalf("line: %s", info.Values[1][1])
+	}
+}
+
+func TestLokiFlushPushesPending(t *testing.T) {
+	server, requests := pushServer(t)
+	w, err := NewLokiWriter(server.URL, LokiConfig{BatchWait: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(`{"message":"one"}` + "\n"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := w.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-requests:
+	default:
+		t.Fatal("Flush returned before the push")
+	}
+}
+
+func TestLokiRetriesServerErrors(t *testing.T) {
+	quietDiagnostics(t)
+	server, requests := pushServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	w, err := NewLokiWriter(server.URL, LokiConfig{BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(`{"message":"one"}` + "\n"))
+	push(t, requests)
+}
+
+func TestLokiRefusesBadConfig(t *testing.T) {
+	cases := map[string]LokiConfig{
+		"ftp://loki":            {},
+		"http://loki:3100":      {Labels: map[string]string{"level": "x"}},
+		"https://loki:3100/":    {Labels: map[string]string{"bad-name": "x"}},
+		"http://loki:3100/push": {BatchWait: "soon"},
+	}
+	for address, cfg := range cases {
+		if _, err := NewLokiWriter(address, cfg); err == nil {
+			t.Fatalf("accepted %s %+v", address, cfg)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"container/heap"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// EntrySource is a Decoder, RangeDecoder or Merger
+type EntrySource interface {
+	Decode() (*Entry, error)
+}
+
+type mergeItem struct {
+	entry  *Entry
+	seq    uint64
+	source int
+}
+
+type mergeHeap []mergeItem
+
+func (x mergeHeap) Len() int { return len(x) }
+func (x mergeHeap) Less(i, j int) bool {
+	if !x[i].entry.Time.Equal(x[j].entry.Time) {
+		return x[i].entry.Time.Before(x[j].entry.Time)
+	}
+	if x[i].seq != x[j].seq {
+		return x[i].seq < x[j].seq
+	}
+	return x[i].source < x[j].source
+}
+func (x mergeHeap) Swap(i, j int)       { x[i], x[j] = x[j], x[i] }
+func (x *mergeHeap) Push(v interface{}) { *x = append(*x, v.(mergeItem)) }
+func (x *mergeHeap) Pop() interface{} {
+	old := *x
+	item := old[len(old)-1]
+	*x = old[:len(old)-1]
+	return item
+}
+
+// Merger interleaves several chronologically ordered sources by time, then seq, then source order
+type Merger struct {
+	sources []EntrySource
+	queue   mergeHeap
+	started bool
+}
+
+func NewMerger(sources ...EntrySource) *Merger {
+	return &Merger{sources: sources}
+}
+
+func (x *Merger) Decode() (*Entry, error) {
+	if !x.started {
+		x.started = true
+		for i := range x.sources {
+			if err := x.next(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(x.queue) == 0 {
+		return nil, io.EOF
+	}
+	item := heap.Pop(&x.queue).(mergeItem)
+	if err := x.next(item.source); err != nil {
+		return nil, err
+	}
+	return item.entry, nil
+}
+
+// next pulls the following well formed entry of one source onto the queue
+func (x *Merger) next(source int) error {
+	for {
+		entry, err := x.sources[source].Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if errors.Is(err, ErrMalformedEntry) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		seq := uint64(0)
+		if text, ok := entry.Field("seq"); ok {
+			seq, _ = strconv.ParseUint(text, 10, 64)
+		}
+		heap.Push(&x.queue, mergeItem{entry: entry, seq: seq, source: source})
+		return nil
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Metrics is a snapshot of counters about the logger itself: Entries are written entries per
+// level, Bytes their encoded size, Sampled the entries dropped by sampling, Dropped the entries a
+// full subscriber or async queue missed, QueueDepth the entries waiting in subscriber channels
+// and async queues and SinkErrors the failed writes per sink, these are never reset by a reload;
+// Components are the entries per configured component and level, the other components counted
+// as COMPONENT_OTHER, SinkBytes the bytes each sink took and SinkDropped the entries the async
+// queue of each sink discarded, also counted in Dropped; these three live on the sinks and
+// components of the active config so they start over for those a reload opens anew, sinks
+// sharing a name are summed
+type Metrics struct {
+	Entries     map[string]uint64            `json:"entries"`
+	Bytes       uint64                       `json:"bytes"`
+	Sampled     uint64                       `json:"sampled"`
+	Dropped     uint64                       `json:"dropped"`
+	QueueDepth  int                          `json:"queue_depth"`
+	SinkErrors  map[string]uint64            `json:"sink_errors"`
+	Components  map[string]map[string]uint64 `json:"components"`
+	SinkBytes   map[string]uint64            `json:"sink_bytes"`
+	SinkDropped map[string]uint64            `json:"sink_dropped"`
+}
+
+// NOTE Str("component", v) takes any value, only components in the config get their own counters
+// so metric labels stay bounded
+const COMPONENT_OTHER = "other"
+
+// entryNames are the level names entries are counted by, see eventIndex
+var entryNames = [...]string{"trace", "debug", "info", "warn", "error", "fatal", "audit"}
+
+// levelCounts counts entries by eventIndex
+type levelCounts [len(entryNames)]atomic.Uint64
+
+func (x *levelCounts) read() map[string]uint64 {
+	counts := map[string]uint64{}
+	for i := range x {
+		if n := x[i].Load(); n > 0 {
+			counts[entryNames[i]] = n
+		}
+	}
+	return counts
+}
+
+var metricEntries = map[string]*atomic.Uint64{
+	"trace": {}, "debug": {}, "info": {}, "warn": {}, "error": {}, "fatal": {}, "audit": {},
+}
+var metricBytes atomic.Uint64
+var metricSampled atomic.Uint64
+var metricDropped atomic.Uint64
+var metricSinkErrors sync.Map
+var metricStderrBytes atomic.Uint64 // written to stderr by InitLogger
+var metricFileBytes atomic.Uint64   // written to LOG_FH
+
+func countEntry(x *Event, out []byte) {
+	index := eventIndex(x.level, x.audit)
+	metricEntries[entryNames[index]].Add(1)
+	metricBytes.Add(uint64(len(out)))
+	if x.component == "" {
+		return
+	}
+	if comp := x.st.components[x.component]; comp != nil {
+		comp.entries[index].Add(1)
+	} else if x.st.otherEntries != nil {
+		x.st.otherEntries[index].Add(1)
+	}
+}
+
+func countSinkError(name string) {
+	counter, _ := metricSinkErrors.LoadOrStore(name, &atomic.Uint64{})
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// eventName is the level name an event is written with
+func eventName(level int, audit bool) string {
+	return entryNames[eventIndex(level, audit)]
+}
+
+func eventIndex(level int, audit bool) int {
+	if audit {
+		return 6
+	}
+	switch level {
+	case LOG_TRACE:
+		return 0
+	case LOG_DEBUG:
+		return 1
+	case LOG_INFO:
+		return 2
+	case LOG_WARN:
+		return 3
+	case LOG_ERROR:
+		return 4
+	}
+	return 5
+}
+
+// ReadMetrics returns the current counters
+func ReadMetrics() Metrics {
+	x := Metrics{
+		Entries:     map[string]uint64{},
+		Bytes:       metricBytes.Load(),
+		Sampled:     metricSampled.Load(),
+		Dropped:     metricDropped.Load(),
+		SinkErrors:  map[string]uint64{},
+		Components:  map[string]map[string]uint64{},
+		SinkBytes:   map[string]uint64{},
+		SinkDropped: map[string]uint64{},
+	}
+	for name, counter := range metricEntries {
+		x.Entries[name] = counter.Load()
+	}
+	metricSinkErrors.Range(func(name, counter interface{}) bool {
+		x.SinkErrors[name.(string)] = counter.(*atomic.Uint64).Load()
+		return true
+	})
+	for _, item := range currentSubscribers() {
+		x.QueueDepth += len(item.ch)
+	}
+	st := active()
+	for name, comp := range st.components {
+		if counts := comp.entries.read(); len(counts) > 0 {
+			x.Components[name] = counts
+		}
+	}
+	if st.otherEntries != nil {
+		if counts := st.otherEntries.read(); len(counts) > 0 {
+			x.Components[COMPONENT_OTHER] = counts
+		}
+	}
+	if n := metricStderrBytes.Load(); n > 0 {
+		x.SinkBytes["stderr"] += n
+	}
+	if n := metricFileBytes.Load(); n > 0 && LOG_FILE != "" {
+		x.SinkBytes[LOG_FILE] += n
+	}
+	for _, item := range append(st.all(), std.extra()...) {
+		if item.queue != nil {
+			x.QueueDepth += item.queue.Backlog()
+		}
+		x.SinkBytes[item.name] += item.bytes.Load()
+		if n := item.dropped.Load(); n > 0 {
+			x.SinkDropped[item.name] += n
+		}
+	}
+	return x
+}
@@ -0,0 +1,152 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AccessOptions tune the access log: Skip lists paths not logged, e.g. "/healthz", with a
+// trailing * matching a prefix; ServerErrors writes 5xx responses at error instead of info;
+// RequestIDHeader is read and echoed, REQUEST_ID_HEADER when empty, and NEW_REQUEST_ID makes up
+// one when the request has none or an invalid one; TrustProxy takes the remote IP from X-Forwarded-For
+type AccessOptions struct {
+	Skip            []string
+	ServerErrors    bool
+	RequestIDHeader string
+	TrustProxy      bool
+}
+
+// AccessLog wraps next with the access log of the default logger, see Logger.Middleware
+func AccessLog(next http.Handler, options ...AccessOptions) http.Handler {
+	return std.Middleware(options...)(next)
+}
+
+// Middleware writes one entry per request with its method, path, status, bytes, duration,
+// remote IP, user agent and request ID; it has the signature chi and gorilla/mux take in Use:
+//
+//	router.Use(log.Default().Middleware(log.AccessOptions{Skip: []string{"/healthz"}}))
+//
+// the request context carries the logger and request ID so log.Ctx(r.Context()) in handlers
+// writes the same request_id
+func (x *Logger) Middleware(options ...AccessOptions) func(http.Handler) http.Handler {
+	cfg := AccessOptions{}
+	if len(options) > 0 {
+		cfg = options[0]
+	}
+	if cfg.RequestIDHeader == "" {
+		cfg.RequestIDHeader = REQUEST_ID_HEADER
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skipPath(cfg.Skip, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			id := r.Header.Get(cfg.RequestIDHeader)
+			if !ValidRequestID(id) {
+				id = NEW_REQUEST_ID()
+			}
+			w.Header().Set(cfg.RequestIDHeader, id)
+			r = r.WithContext(WithRequestID(x.WithContext(r.Context()), id))
+
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			// NOTE a handler that panics is written with the 500 net/http answers it with, the
+			// panic goes on up unchanged
+			defer func() {
+				status := recorder.status
+				if !completed {
+					status = http.StatusInternalServerError
+				}
+				level, name := LOG_INFO, "info"
+				if cfg.ServerErrors && status >= 500 {
+					level, name = LOG_ERROR, "error"
+				}
+				e, ok := x.leveled(level, name).(*Event)
+				if !ok || e.ignore {
+					return
+				}
+				// NOTE the caller would be this middleware, not the handler
+				e.caller = true
+				e.Str("method", r.Method).
+					Str("path", r.URL.Path).
+					Int("status", status).
+					Int64("bytes", recorder.bytes).
+					Dur("duration", time.Since(start)).
+					Str("remote_ip", remoteIP(r, cfg.TrustProxy)).
+					Str("user_agent", r.UserAgent()).
+					Str("request_id", id).
+					Msg("request")
+			}()
+			next.ServeHTTP(recorder, r)
+			completed = true
+		})
+	}
+}
+
+func skipPath(skip []string, path string) bool {
+	for _, item := range skip {
+		if prefix, ok := strings.CutSuffix(item, "*"); ok && strings.HasPrefix(path, prefix) || item == path {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteIP(r *http.Request, trustProxy bool) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); trustProxy && forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		return strings.TrimSpace(first)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// responseRecorder keeps the status and size of a response, Unwrap lets http.ResponseController
+// reach the flusher and hijacker of the underlying writer
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (x *responseRecorder) WriteHeader(status int) {
+	if !x.wroteHeader {
+		x.status, x.wroteHeader = status, true
+	}
+	x.ResponseWriter.WriteHeader(status)
+}
+
+func (x *responseRecorder) Write(p []byte) (int, error) {
+	x.wroteHeader = true
+	n, err := x.ResponseWriter.Write(p)
+	x.bytes += int64(n)
+	return n, err
+}
+
+func (x *responseRecorder) Unwrap() http.ResponseWriter {
+	return x.ResponseWriter
+}
+
+func (x *responseRecorder) Flush() {
+	if flusher, ok := x.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (x *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := x.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("hijack not supported")
+}
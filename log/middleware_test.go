@@ -0,0 +1,64 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareWritesAccessEntry(t *testing.T) {
+	entries := capture(t, Config{Level: "info"})
+	var seen string
+	handler := Default().Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("made"))
+	}))
+	r := httptest.NewRequest(http.MethodPost, "/items", nil)
+	r.Header.Set(REQUEST_ID_HEADER, "req-1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	entry := received(t, entries)
+	for key, want := range map[string]string{"method": "POST", "path": "/items", "status": "201", "bytes": "4", "request_id": "req-1"} {
+		if value, _ := entry.Field(key); value != want {
+			t.Fatalf("%s: expected %s in %s", key, want, entry.Raw)
+		}
+	}
+	if seen != "req-1" || w.Header().Get(REQUEST_ID_HEADER) != "req-1" {
+		t.Fatalf("request id in the handler %q and the response %q", seen, w.Header().Get(REQUEST_ID_HEADER))
+	}
+}
+
+func TestMiddlewareSkipsPaths(t *testing.T) {
+	entries := capture(t, Config{Level: "info"})
+	handler := Default().Middleware(AccessOptions{Skip: []string{"/healthz", "/static/*"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, path := range []string{"/healthz", "/static/app.js", "/api"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	if entry := received(t, entries); entry.Fields["path"] != "/api" {
+		t.Fatalf("logged a skipped path: %s", entry.Raw)
+	}
+	quiet(t, entries, 20*time.Millisecond)
+}
+
+func TestMiddlewareLogsPanics(t *testing.T) {
+	entries := capture(t, Config{Level: "info"})
+	handler := Default().Middleware(AccessOptions{ServerErrors: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("the panic did not reach the server")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/crash", nil))
+	}()
+	entry := received(t, entries)
+	if status, _ := entry.Field("status"); status != "500" || entry.Level != "error" {
+		t.Fatalf("access entry of a panic: %s", entry.Raw)
+	}
+}
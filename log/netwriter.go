@@ -0,0 +1,220 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NOTE NET_BUFFER is the number of lines a NetWriter holds while disconnected, the oldest
+// are kept and new ones dropped and counted in Metrics.Dropped once it is full; reconnects
+// wait NET_BACKOFF_MIN, doubling up to NET_BACKOFF_MAX
+const (
+	NET_BUFFER        = 8192
+	NET_BACKOFF_MIN   = 100 * time.Millisecond
+	NET_BACKOFF_MAX   = 30 * time.Second
+	NET_DIAL_TIMEOUT  = 5 * time.Second
+	NET_CLOSE_TIMEOUT = 5 * time.Second
+)
+
+var ErrNetWriterClosed = errors.New("net writer closed")
+
+// NetOptions tune a NetWriter, zero values mean NET_BUFFER lines and no TLS
+type NetOptions struct {
+	Buffer int
+	TLS    *tls.Config
+}
+
+// NetWriter ships lines to a collector over tcp, udp or a unix socket from a background
+// goroutine, so Write never waits on the network; it reconnects with backoff and sends what
+// was buffered meanwhile
+type NetWriter struct {
+	network   string
+	address   string
+	tls       *tls.Config
+	ch        chan []byte
+	flush     chan chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	conn      net.Conn
+	failing   atomic.Bool
+}
+
+// NewNetWriter starts shipping to address, e.g. log.AddWriter(log.NewNetWriter("tcp", "collector:5170"))
+func NewNetWriter(network, address string, options ...NetOptions) *NetWriter {
+	cfg := NetOptions{}
+	if len(options) > 0 {
+		cfg = options[0]
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = NET_BUFFER
+	}
+	x := &NetWriter{network: network, address: address, tls: cfg.TLS, ch: make(chan []byte, cfg.Buffer), flush: make(chan chan struct{}), done: make(chan struct{}), stopped: make(chan struct{})}
+	go x.run()
+	return x
+}
+
+func (x *NetWriter) Write(p []byte) (int, error) {
+	select {
+	case <-x.done:
+		return 0, ErrNetWriterClosed
+	default:
+	}
+	select {
+	case x.ch <- append([]byte{}, p...):
+	default:
+		metricDropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Backlog is the number of lines waiting to be sent
+func (x *NetWriter) Backlog() int {
+	return len(x.ch)
+}
+
+func (x *NetWriter) datagram() bool {
+	return x.network == "udp" || x.network == "udp4" || x.network == "udp6" || x.network == "unixgram"
+}
+
+func (x *NetWriter) dial() error {
+	dialer := &net.Dialer{Timeout: NET_DIAL_TIMEOUT}
+	var conn net.Conn
+	var err error
+	if x.tls != nil {
+		conn, err = tls.DialWithDialer(dialer, x.network, x.address, x.tls)
+	} else {
+		conn, err = dialer.Dial(x.network, x.address)
+	}
+	if err != nil {
+		if !x.failing.Swap(true) {
+			diagnose("dial", x.address, err)
+		}
+		return err
+	}
+	if x.failing.Swap(false) {
+		diagnose("recover", x.address, nil)
+	}
+	x.conn = conn
+	return nil
+}
+
+// connect dials until it succeeds, false means the writer was closed meanwhile
+func (x *NetWriter) connect() bool {
+	backoff := NET_BACKOFF_MIN
+	for x.conn == nil {
+		if x.dial() == nil {
+			return true
+		}
+		select {
+		case <-x.done:
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, NET_BACKOFF_MAX)
+	}
+	return true
+}
+
+func (x *NetWriter) run() {
+	defer close(x.stopped)
+	for {
+		select {
+		case line := <-x.ch:
+			x.send(x.batch(line), true)
+		case reply := <-x.flush:
+			x.drain()
+			close(reply)
+		case <-x.done:
+			x.drain()
+			if x.conn != nil {
+				x.conn.Close()
+			}
+			return
+		}
+	}
+}
+
+// batch joins what is queued into one write on stream connections
+func (x *NetWriter) batch(line []byte) [][]byte {
+	lines := [][]byte{line}
+	for len(lines) < ASYNC_BATCH {
+		select {
+		case next := <-x.ch:
+			lines = append(lines, next)
+		default:
+			return lines
+		}
+	}
+	return lines
+}
+
+// send keeps retrying through reconnects while retry is set, a batch is resent whole after a
+// failed write so the collector may see a line twice but never half of one
+func (x *NetWriter) send(lines [][]byte, retry bool) {
+	payload := lines
+	if !x.datagram() {
+		joined := []byte{}
+		for _, line := range lines {
+			joined = append(joined, line...)
+		}
+		payload = [][]byte{joined}
+	}
+	for len(payload) > 0 {
+		if x.conn == nil && !(retry && x.connect()) {
+			metricDropped.Add(uint64(len(lines)))
+			return
+		}
+		if _, err := x.conn.Write(payload[0]); err != nil {
+			if !x.failing.Swap(true) {
+				diagnose("write", x.address, err)
+			}
+			x.conn.Close()
+			x.conn = nil
+			if !retry {
+				metricDropped.Add(uint64(len(lines)))
+				return
+			}
+			continue
+		}
+		payload = payload[1:]
+	}
+}
+
+// drain sends what is queued with a single connection attempt, for Flush and Close
+func (x *NetWriter) drain() {
+	if x.conn == nil {
+		x.dial()
+	}
+	for {
+		select {
+		case line := <-x.ch:
+			x.send(x.batch(line), false)
+		default:
+			return
+		}
+	}
+}
+
+// Flush sends the buffered lines before ctx is done, Logger.Flush calls it so a Fatal entry
+// and those before it reach the collector before EXIT
+func (x *NetWriter) Flush(ctx context.Context) error {
+	return flushLoop(ctx, x.flush, x.stopped, "net")
+}
+
+// Close sends the buffered lines, waiting up to NET_CLOSE_TIMEOUT
+func (x *NetWriter) Close() error {
+	x.closeOnce.Do(func() { close(x.done) })
+	select {
+	case <-x.stopped:
+		return nil
+	case <-time.After(NET_CLOSE_TIMEOUT):
+		return errors.New("net writer: timed out sending buffered lines")
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NOTE an OTLPWriter exports when OTLP_BATCH records are pending or the oldest has waited
+// OTLP_WAIT and retries a failed export OTLP_RETRIES times with the NetWriter backoff
+const (
+	OTLP_BATCH   = 512
+	OTLP_WAIT    = time.Second
+	OTLP_BUFFER  = 8192
+	OTLP_RETRIES = 5
+	OTLP_LOGS    = "/v1/logs"
+)
+
+// OTLP_SEVERITIES are the OTel severity numbers of the levels, audit entries are INFO2
+var OTLP_SEVERITIES = map[string]int{
+	"trace": 1, "debug": 5, "info": 9, "audit": 10, "warn": 13, "error": 17, "fatal": 21, "panic": 21,
+}
+
+// OTLPRecord is an entry in the OTel log data model, attribute values are string, json.Number,
+// bool, []interface{} or map[string]interface{} as ParseEntry decodes them
+type OTLPRecord struct {
+	Time           time.Time
+	SeverityNumber int
+	SeverityText   string
+	Body           string
+	Attributes     map[string]interface{}
+	TraceID        []byte // 16 bytes from the trace_id field, nil without one
+	SpanID         []byte // 8 bytes from the span_id field, nil without one
+}
+
+// OTLPExporter sends a batch of records with the attributes of the resource they come from
+type OTLPExporter interface {
+	Export(ctx context.Context, resource map[string]string, records []OTLPRecord) error
+}
+
+// NewOTLPExporter builds the exporter of a protocol for the collector at endpoint
+type NewOTLPExporter func(endpoint string, headers map[string]string) (OTLPExporter, error)
+
+// NOTE http/json needs nothing beyond the standard library, import github.com/osintami/sloan/otlp
+// to register grpc
+var otlpProtocols = map[string]NewOTLPExporter{
+	"http/json": newOTLPHTTP,
+}
+
+// RegisterOTLPProtocol adds a protocol for OTLPConfig.Protocol
+func RegisterOTLPProtocol(name string, fn NewOTLPExporter) {
+	otlpProtocols[strings.ToLower(name)] = fn
+}
+
+// OTLPConfig applies to sinks of type "otlp", whose Path is the collector endpoint, e.g.
+// http://otel-collector:4318; Protocol is http/json (the default) or one added with
+// RegisterOTLPProtocol, Headers are sent with every export with ${VAR} expanded from the
+// environment and Resource adds to service.name, service.version and host.name
+type OTLPConfig struct {
+	Protocol  string            `json:"protocol" yaml:"protocol" toml:"protocol"`
+	Headers   map[string]string `json:"headers" yaml:"headers" toml:"headers"`
+	Resource  map[string]string `json:"resource" yaml:"resource" toml:"resource"`
+	BatchSize int               `json:"batch_size" yaml:"batch_size" toml:"batch_size"`
+	BatchWait string            `json:"batch_wait" yaml:"batch_wait" toml:"batch_wait"`
+}
+
+// OTLPWriter exports entries as OTel log records from a background goroutine, so Write never
+// waits on the collector
+type OTLPWriter struct {
+	endpoint  string
+	exporter  OTLPExporter
+	resource  map[string]string
+	batch     int
+	wait      time.Duration
+	ch        chan OTLPRecord
+	flush     chan chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	failing   atomic.Bool
+}
+
+func NewOTLPWriter(endpoint string, cfg OTLPConfig) (*OTLPWriter, error) {
+	protocol := strings.ToLower(cfg.Protocol)
+	if protocol == "" {
+		protocol = "http/json"
+	}
+	fn, ok := otlpProtocols[protocol]
+	if !ok {
+		return nil, fmt.Errorf("otlp: unknown protocol %q, use %s", cfg.Protocol, otlpProtocolNames())
+	}
+	headers := map[string]string{}
+	for key, value := range cfg.Headers {
+		headers[key] = os.ExpandEnv(value)
+	}
+	exporter, err := fn(endpoint, headers)
+	if err != nil {
+		return nil, err
+	}
+	x := &OTLPWriter{endpoint: endpoint, exporter: exporter, resource: map[string]string{}, batch: cfg.BatchSize, wait: OTLP_WAIT,
+		ch: make(chan OTLPRecord, OTLP_BUFFER), flush: make(chan chan struct{}), done: make(chan struct{}), stopped: make(chan struct{})}
+	if x.batch <= 0 {
+		x.batch = OTLP_BATCH
+	}
+	if cfg.BatchWait != "" {
+		if x.wait, err = time.ParseDuration(cfg.BatchWait); err != nil || x.wait <= 0 {
+			return nil, fmt.Errorf("otlp: batch_wait %q is not a positive duration", cfg.BatchWait)
+		}
+	}
+	fields := StandardFields("")
+	x.resource["service.name"] = fields["service"]
+	for key, name := range map[string]string{"version": "service.version", "hostname": "host.name"} {
+		if value, ok := fields[key]; ok {
+			x.resource[name] = value
+		}
+	}
+	for key, value := range cfg.Resource {
+		x.resource[key] = value
+	}
+	go x.run()
+	return x, nil
+}
+
+func otlpProtocolNames() string {
+	names := []string{}
+	for name := range otlpProtocols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// otlpRecord maps an entry to the log data model, trace_id and span_id become the record's
+// trace context when they are valid hex IDs
+func otlpRecord(entry *Entry) OTLPRecord {
+	x := OTLPRecord{Time: entry.Time, SeverityText: strings.ToUpper(entry.Level), Body: entry.Message, Attributes: entry.Fields}
+	x.SeverityNumber = OTLP_SEVERITIES[strings.ToLower(entry.Level)]
+	if id, err := hex.DecodeString(fmt.Sprint(entry.Fields["trace_id"])); err == nil && len(id) == 16 {
+		x.TraceID = id
+		delete(x.Attributes, "trace_id")
+	}
+	if id, err := hex.DecodeString(fmt.Sprint(entry.Fields["span_id"])); err == nil && len(id) == 8 {
+		x.SpanID = id
+		delete(x.Attributes, "span_id")
+	}
+	return x
+}
+
+// Write queues each entry of p, a full buffer drops it
+func (x *OTLPWriter) Write(p []byte) (int, error) {
+	select {
+	case <-x.done:
+		return 0, errors.New("otlp writer closed")
+	default:
+	}
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		entry, err := ParseEntry(line)
+		if err != nil {
+			continue
+		}
+		select {
+		case x.ch <- otlpRecord(entry):
+		default:
+			metricDropped.Add(1)
+		}
+	}
+	return len(p), nil
+}
+
+func (x *OTLPWriter) run() {
+	defer close(x.stopped)
+	pending := []OTLPRecord{}
+	timer := time.NewTimer(x.wait)
+	timer.Stop()
+	for {
+		select {
+		case record := <-x.ch:
+			if len(pending) == 0 {
+				timer.Reset(x.wait)
+			}
+			if pending = append(pending, record); len(pending) >= x.batch {
+				timer.Stop()
+				x.export(pending, true)
+				pending = pending[:0]
+			}
+		case <-timer.C:
+			x.export(pending, true)
+			pending = pending[:0]
+		case reply := <-x.flush:
+			timer.Stop()
+			pending = x.drain(pending)
+			close(reply)
+		case <-x.done:
+			timer.Stop()
+			x.drain(pending)
+			return
+		}
+	}
+}
+
+// drain exports pending and everything queued without retrying, for Flush and Close
+func (x *OTLPWriter) drain(pending []OTLPRecord) []OTLPRecord {
+	for len(x.ch) > 0 {
+		pending = append(pending, <-x.ch)
+	}
+	for len(pending) > 0 {
+		n := min(len(pending), x.batch)
+		x.export(pending[:n], false)
+		pending = pending[n:]
+	}
+	return pending[:0]
+}
+
+func (x *OTLPWriter) export(records []OTLPRecord, retry bool) {
+	if len(records) == 0 {
+		return
+	}
+	backoff := NET_BACKOFF_MIN
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), NET_CLOSE_TIMEOUT)
+		err := x.exporter.Export(ctx, x.resource, records)
+		cancel()
+		if err == nil {
+			if x.failing.Swap(false) {
+				diagnose("recover", x.endpoint, nil)
+			}
+			return
+		}
+		if !x.failing.Swap(true) {
+			diagnose("export", x.endpoint, err)
+		}
+		if !retry || attempt == OTLP_RETRIES {
+			metricDropped.Add(uint64(len(records)))
+			return
+		}
+		select {
+		case <-x.done:
+			retry = false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, NET_BACKOFF_MAX)
+	}
+}
+
+// Flush exports what is pending before ctx is done, Logger.Flush calls it so a Fatal entry and
+// those before it reach the collector before EXIT
+func (x *OTLPWriter) Flush(ctx context.Context) error {
+	return flushLoop(ctx, x.flush, x.stopped, "otlp")
+}
+
+// Close exports what is pending, waiting up to NET_CLOSE_TIMEOUT, then closes the exporter
+func (x *OTLPWriter) Close() error {
+	x.closeOnce.Do(func() { close(x.done) })
+	select {
+	case <-x.stopped:
+	case <-time.After(NET_CLOSE_TIMEOUT):
+		return errors.New("otlp writer: timed out exporting pending entries")
+	}
+	if c, ok := x.exporter.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// otlpHTTP posts ExportLogsServiceRequest in the OTLP JSON encoding, where IDs are hex
+type otlpHTTP struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newOTLPHTTP(endpoint string, headers map[string]string) (OTLPExporter, error) {
+	target, err := url.Parse(endpoint)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("otlp: endpoint %q is not an http:// or https:// URL", endpoint)
+	}
+	if target.Path == "" || target.Path == "/" {
+		target.Path = OTLP_LOGS
+	}
+	return &otlpHTTP{url: target.String(), headers: headers, client: &http.Client{}}, nil
+}
+
+func (x *otlpHTTP) Export(ctx context.Context, resource map[string]string, records []OTLPRecord) error {
+	logs := []map[string]interface{}{}
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+	for _, record := range records {
+		item := map[string]interface{}{
+			"timeUnixNano":         strconv.FormatInt(record.Time.UnixNano(), 10),
+			"observedTimeUnixNano": observed,
+			"severityNumber":       record.SeverityNumber,
+			"severityText":         record.SeverityText,
+			"body":                 map[string]interface{}{"stringValue": record.Body},
+			"attributes":           otlpAttributes(record.Attributes),
+		}
+		if record.TraceID != nil {
+			item["traceId"] = hex.EncodeToString(record.TraceID)
+		}
+		if record.SpanID != nil {
+			item["spanId"] = hex.EncodeToString(record.SpanID)
+		}
+		logs = append(logs, item)
+	}
+	attributes := map[string]interface{}{}
+	for key, value := range resource {
+		attributes[key] = value
+	}
+	body, err := json.Marshal(map[string]interface{}{"resourceLogs": []interface{}{map[string]interface{}{
+		"resource":  map[string]interface{}{"attributes": otlpAttributes(attributes)},
+		"scopeLogs": []interface{}{map[string]interface{}{"scope": map[string]string{"name": "sloan"}, "logRecords": logs}},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, x.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range x.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// otlpAttributes encodes fields as KeyValue pairs sorted by key
+func otlpAttributes(fields map[string]interface{}) []map[string]interface{} {
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attributes := []map[string]interface{}{}
+	for _, key := range keys {
+		attributes = append(attributes, map[string]interface{}{"key": key, "value": otlpValue(fields[key])})
+	}
+	return attributes
+}
+
+// otlpValue encodes an AnyValue, 64 bit integers are strings in the OTLP JSON encoding
+func otlpValue(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return map[string]interface{}{"intValue": strconv.FormatInt(n, 10)}
+		}
+		f, _ := v.Float64()
+		return map[string]interface{}{"doubleValue": f}
+	case []interface{}:
+		values := []map[string]interface{}{}
+		for _, item := range v {
+			values = append(values, otlpValue(item))
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	case map[string]interface{}:
+		return map[string]interface{}{"kvlistValue": map[string]interface{}{"values": otlpAttributes(v)}}
+	}
+	return map[string]interface{}{}
+}
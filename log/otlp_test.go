@@ -0,0 +1,86 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const otlpLine = `{"time":"2025-03-09T12:00:00Z","level":"error","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","attempt":3,"message":"lookup failed"}`
+
+// otlpCapture is an exporter that hands every batch to the test
+type otlpCapture chan []OTLPRecord
+
+func (x otlpCapture) Export(ctx context.Context, resource map[string]string, records []OTLPRecord) error {
+	x <- append([]OTLPRecord{}, records...)
+	return nil
+}
+
+func TestOTLPRecord(t *testing.T) {
+	entry, err := ParseEntry([]byte(otlpLine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := otlpRecord(entry)
+	if record.SeverityNumber != 17 || record.SeverityText != "ERROR" || record.Body != "lookup failed" {
+		t.Fatalf("record: %+v", record)
+	}
+	if len(record.TraceID) != 16 || len(record.SpanID) != 8 {
+		t.Fatalf("trace context: %x %x", record.TraceID, record.SpanID)
+	}
+	if _, ok := record.Attributes["trace_id"]; ok || record.Attributes["attempt"] != json.Number("3") {
+		t.Fatalf("attributes: %v", record.Attributes)
+	}
+}
+
+func TestOTLPBatchesThroughProtocol(t *testing.T) {
+	batches := make(otlpCapture, 4)
+	RegisterOTLPProtocol("test", func(endpoint string, headers map[string]string) (OTLPExporter, error) {
+		return batches, nil
+	})
+	defer delete(otlpProtocols, "test")
+	w, err := NewOTLPWriter("collector:4317", OTLPConfig{Protocol: "TEST", BatchSize: 2, BatchWait: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(otlpLine + "\n" + otlpLine + "\n" + otlpLine + "\n"))
+	if records := <-batches; len(records) != 2 {
+		t.Fatalf("expected a batch of 2, got %d", len(records))
+	}
+	flushWriter(t, w)
+	if records := <-batches; len(records) != 1 {
+		t.Fatalf("expected Flush to export the rest, got %d", len(records))
+	}
+}
+
+func TestOTLPHTTPJSON(t *testing.T) {
+	t.Setenv("OTLP_KEY", "hunter2")
+	server, requests := pushServer(t)
+	w, err := NewOTLPWriter(server.URL, OTLPConfig{Headers: map[string]string{"api-key": "${OTLP_KEY}"}, Resource: map[string]string{"deployment.environment": "test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(otlpLine + "\n"))
+	flushWriter(t, w)
+
+	item := push(t, requests)
+	if item.path != OTLP_LOGS || item.header.Get("api-key") != "hunter2" {
+		t.Fatalf("export to %s with %v", item.path, item.header)
+	}
+	body := string(item.body)
+	for _, want := range []string{`"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"`, `"severityNumber":17`, `"intValue":"3"`, `"key":"deployment.environment"`, `"key":"service.name"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %s in %s", want, body)
+		}
+	}
+}
+
+func TestOTLPRefusesUnknownProtocol(t *testing.T) {
+	if _, err := NewOTLPWriter("http://collector:4318", OTLPConfig{Protocol: "carrier-pigeon"}); err == nil || !strings.Contains(err.Error(), "http/json") {
+		t.Fatalf("expected the known protocols in the error, got %v", err)
+	}
+}
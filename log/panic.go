@@ -0,0 +1,73 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"strings"
+)
+
+// Go runs fn in a goroutine that logs and swallows a panic instead of crashing the process,
+// labels are key/value pairs attached to the goroutine for pprof and to the panic entry
+func Go(fn func(), labels ...string) {
+	go func() {
+		defer CapturePanic(labels...)
+		if len(labels) < 2 {
+			fn()
+			return
+		}
+		pprof.Do(context.Background(), pprof.Labels(labels...), func(context.Context) { fn() })
+	}()
+}
+
+// CapturePanic recovers a panic and logs it at error level with the stack, call it as
+// defer log.CapturePanic("worker", "scan") directly since recover only works there
+func CapturePanic(labels ...string) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	logPanic(std.Error(), value, labels...)
+}
+
+// logPanic writes a recovered value with the stack of the goroutine and an incident ID, which it
+// returns so the panic can be quoted to whoever hit it
+func logPanic(e ILogger, value interface{}, labels ...string) string {
+	x := e.(*Event)
+	id := NEW_REQUEST_ID()
+	if x.ignore {
+		return id
+	}
+	for i := 0; i+1 < len(labels); i += 2 {
+		x.Str(labels[i], labels[i+1])
+	}
+	x.Str("incident_id", id)
+	x.RawJSON("panic", marshal(fmt.Sprint(value)))
+	x.RawJSON("stack", marshal(string(debug.Stack())))
+	x.depth = panicDepth()
+	x.Msg("recovered panic")
+	return id
+}
+
+// panicDepth counts the frames from CapturePanic to the function that panicked
+func panicDepth() int {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	depth, panicking := 0, false
+	for {
+		frame, more := frames.Next()
+		if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			return depth
+		}
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			return 0
+		}
+		depth++
+	}
+}
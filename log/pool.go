@@ -0,0 +1,35 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "sync"
+
+// NOTE events are pooled and must not be used after Msg; buffers grown past EVENT_BUF_MAX, e.g.
+// by a stack dump, are left to the garbage collector instead of being kept in the pool
+const EVENT_BUF_MAX = 64 * 1024
+
+var eventPool = sync.Pool{New: func() interface{} {
+	return &Event{buf: make([]byte, 0, 512)}
+}}
+
+// disabled is shared by every filtered event so they allocate nothing, it is never written to
+var disabled = &Event{ignore: true}
+
+func newEvent(logger *Logger, st *state, level int) *Event {
+	x := eventPool.Get().(*Event)
+	x.logger, x.st, x.level, x.component = logger, st, level, logger.component
+	if st.inflight != nil {
+		st.inflight.Add(1)
+	}
+	return x
+}
+
+func (x *Event) release() {
+	if x.st != nil && x.st.inflight != nil {
+		x.st.inflight.Add(-1)
+	}
+	if cap(x.buf) > EVENT_BUF_MAX {
+		return
+	}
+	*x = Event{buf: x.buf[:0]}
+	eventPool.Put(x)
+}
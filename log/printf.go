@@ -0,0 +1,31 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "fmt"
+
+// Debugf, Infof, Warnf and Errorf ease migrating from fmt and the standard log package,
+// prefer fields for anything worth searching on
+func Debugf(format string, args ...interface{}) {
+	printf(Debug(), format, args)
+}
+
+func Infof(format string, args ...interface{}) {
+	printf(Info(), format, args)
+}
+
+func Warnf(format string, args ...interface{}) {
+	printf(Warn(), format, args)
+}
+
+func Errorf(format string, args ...interface{}) {
+	printf(Error(), format, args)
+}
+
+func printf(logger ILogger, format string, args []interface{}) {
+	x := logger.(*Event)
+	if x.ignore {
+		return
+	}
+	x.depth = 2
+	x.Msg(fmt.Sprintf(format, args...))
+}
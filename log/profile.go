@@ -0,0 +1,104 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+// PROFILES are the presets selectable with Config.Profile or SLOAN_PROFILE
+var PROFILES = map[string]Config{
+	"dev": {
+		Level:  "debug",
+		Format: "json",
+		Caller: true,
+		Sinks:  []SinkConfig{{Type: "stderr"}},
+	},
+	"prod": {
+		Level:    "info",
+		Format:   "json",
+		Sinks:    []SinkConfig{{Type: "stderr"}},
+		Sampling: SamplingConfig{Every: 10, Levels: []string{"debug"}},
+	},
+	"test": {
+		Level:  "trace",
+		Format: "json",
+		Caller: true,
+		Sinks:  []SinkConfig{{Type: "stdout"}},
+	},
+	"quiet": {
+		Level:  "error",
+		Format: "json",
+		Sinks:  []SinkConfig{{Type: "stderr"}},
+	},
+}
+
+// withProfile starts from the named profile and lets every field set in x win, flags can only be turned on
+func (x Config) withProfile() Config {
+	base, ok := PROFILES[x.Profile]
+	if !ok {
+		return x
+	}
+	base.Profile = x.Profile
+	if x.Level != "" {
+		base.Level = x.Level
+	}
+	if x.Format != "" {
+		base.Format = x.Format
+	}
+	base.Caller = base.Caller || x.Caller
+	base.CallerFunc = base.CallerFunc || x.CallerFunc
+	if x.CallerSkip != 0 {
+		base.CallerSkip = x.CallerSkip
+	}
+	if x.CallerTrim != "" {
+		base.CallerTrim = x.CallerTrim
+	}
+	base.Sequence = base.Sequence || x.Sequence
+	base.DryRun = base.DryRun || x.DryRun
+	base.ErrorStackTraces = base.ErrorStackTraces || x.ErrorStackTraces
+	if x.ErrorStackDepth != 0 {
+		base.ErrorStackDepth = x.ErrorStackDepth
+	}
+	if len(x.Sinks) > 0 {
+		base.Sinks = x.Sinks
+	}
+	if x.Rotation != (RotationConfig{}) {
+		base.Rotation = x.Rotation
+	}
+	if len(x.Redact.Keys) > 0 || len(x.Redact.Patterns) > 0 {
+		base.Redact = x.Redact
+	}
+	if x.Sampling.Every != 0 || x.Sampling.Burst != 0 {
+		base.Sampling = x.Sampling
+	}
+	base.Audit = x.Audit
+	if x.Dedup.Window != "" {
+		base.Dedup = x.Dedup
+	}
+	if x.Async.Enabled {
+		base.Async = x.Async
+	}
+	if x.Retention.Days != 0 {
+		base.Retention = x.Retention
+	}
+	base.EncryptFields = x.EncryptFields
+	base.StaticFields = x.StaticFields
+	if x.Timestamp != "" {
+		base.Timestamp = x.Timestamp
+	}
+	base.UTC = base.UTC || x.UTC
+	if x.TimeKey != "" {
+		base.TimeKey = x.TimeKey
+	}
+	if x.LevelKey != "" {
+		base.LevelKey = x.LevelKey
+	}
+	if x.MessageKey != "" {
+		base.MessageKey = x.MessageKey
+	}
+	if x.DurationUnit != "" {
+		base.DurationUnit = x.DurationUnit
+	}
+	if x.TimeLayout != "" {
+		base.TimeLayout = x.TimeLayout
+	}
+	base.Components = x.Components
+	base.Levels = x.Levels
+	return base
+}
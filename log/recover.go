@@ -0,0 +1,84 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"net/http"
+)
+
+// RecoverOptions tune RecoverAndLog and the recovery middleware: Fatal writes the panic at fatal,
+// ending the process after the flush; Repanic panics again with the value once it is written,
+// so a supervisor or an outer recover still sees it
+type RecoverOptions struct {
+	Fatal   bool
+	Repanic bool
+}
+
+// RecoverAndLog recovers a panic and writes it with the goroutine stack and an incident ID, at
+// error unless options ask for fatal; defer it directly since recover only works there:
+//
+//	defer log.RecoverAndLog(log.RecoverOptions{Repanic: true})
+func RecoverAndLog(options ...RecoverOptions) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	cfg := recoverOptions(options)
+	logPanic(panicEvent(std, cfg), value)
+	if cfg.Repanic {
+		panic(value)
+	}
+}
+
+// Recover wraps next with the recovery middleware of the default logger, see Logger.Recoverer
+func Recover(next http.Handler, options ...RecoverOptions) http.Handler {
+	return std.Recoverer(options...)(next)
+}
+
+// Recoverer catches panics of the handlers it wraps and writes them with the stack, the method,
+// path and remote IP of the request, the request ID of its context and an incident ID, then
+// answers 500 quoting the incident ID unless options ask to repanic; put it inside Middleware
+// so the access log records the 500. http.ErrAbortHandler is passed on untouched
+func (x *Logger) Recoverer(options ...RecoverOptions) func(http.Handler) http.Handler {
+	cfg := recoverOptions(options)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+				logger := x
+				if id := RequestID(r.Context()); id != "" {
+					logger = x.With().Str("request_id", id).Logger()
+				}
+				id := logPanic(panicEvent(logger, cfg), value,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"remote_ip", remoteIP(r, false))
+				if cfg.Repanic {
+					panic(value)
+				}
+				w.Header().Set("X-Incident-ID", id)
+				http.Error(w, "internal server error, incident "+id, http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func recoverOptions(options []RecoverOptions) RecoverOptions {
+	if len(options) > 0 {
+		return options[0]
+	}
+	return RecoverOptions{}
+}
+
+func panicEvent(logger *Logger, cfg RecoverOptions) ILogger {
+	if cfg.Fatal {
+		return logger.Fatal()
+	}
+	return logger.Error()
+}
@@ -0,0 +1,140 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const REDACT_MASK = "[REDACTED]"
+
+type redactor struct {
+	mask     string
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+func newRedactor(cfg RedactConfig) (*redactor, error) {
+	if len(cfg.Keys) == 0 && len(cfg.Patterns) == 0 {
+		return nil, nil
+	}
+	x := &redactor{mask: cfg.Mask, keys: map[string]bool{}}
+	if x.mask == "" {
+		x.mask = REDACT_MASK
+	}
+	for _, key := range cfg.Keys {
+		x.keys[strings.ToLower(key)] = true
+	}
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redact pattern %q: %w", pattern, err)
+		}
+		x.patterns = append(x.patterns, re)
+	}
+	return x, nil
+}
+
+func redactValue(st *state, key, value string) string {
+	x, registered := st.redact, secrets.Load()
+	if x == nil && registered == nil {
+		return value
+	}
+	if redactedKey(st, key) {
+		return x.maskOr(REDACT_MASK)
+	}
+	return redactText(st, value)
+}
+
+func redactedKey(st *state, key string) bool {
+	x, registered := st.redact, secrets.Load()
+	key = strings.ToLower(key)
+	return (x != nil && x.keys[key]) || (registered != nil && registered.keys[key])
+}
+
+func redactText(st *state, value string) string {
+	x, registered := st.redact, secrets.Load()
+	if x != nil {
+		value = x.text(value)
+	}
+	if registered != nil {
+		for _, re := range registered.patterns {
+			value = re.ReplaceAllString(value, x.maskOr(REDACT_MASK))
+		}
+	}
+	return value
+}
+
+func (x *redactor) maskOr(mask string) string {
+	if x == nil {
+		return mask
+	}
+	return x.mask
+}
+
+func (x *redactor) text(value string) string {
+	for _, re := range x.patterns {
+		value = re.ReplaceAllString(value, x.mask)
+	}
+	return value
+}
+
+// NOTE the secrets registry applies on top of every config's redact rules and survives reloads
+var secretsMu sync.Mutex
+var secretPatterns = map[string]string{}
+var secretKeys = map[string]bool{}
+var secrets atomic.Pointer[redactor]
+
+// RegisterSecretPattern adds or replaces a named regex whose matches are masked in every entry
+func RegisterSecretPattern(name, pattern string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("secret pattern %s: %w", name, err)
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secretPatterns[name] = pattern
+	return compileSecrets()
+}
+
+// RegisterSecretKeys masks the whole value of fields with these keys in every entry
+func RegisterSecretKeys(keys ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, key := range keys {
+		secretKeys[strings.ToLower(key)] = true
+	}
+	compileSecrets()
+}
+
+// SecretRules returns the registered keys and patterns as a RedactConfig
+func SecretRules() RedactConfig {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	return secretRules()
+}
+
+func secretRules() RedactConfig {
+	cfg := RedactConfig{}
+	for key := range secretKeys {
+		cfg.Keys = append(cfg.Keys, key)
+	}
+	for _, pattern := range secretPatterns {
+		cfg.Patterns = append(cfg.Patterns, pattern)
+	}
+	sort.Strings(cfg.Keys)
+	sort.Strings(cfg.Patterns)
+	return cfg
+}
+
+func compileSecrets() error {
+	x, err := newRedactor(secretRules())
+	if err != nil {
+		return err
+	}
+	secrets.Store(x)
+	return nil
+}
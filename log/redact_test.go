@@ -0,0 +1,24 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactMessage(t *testing.T) {
+	if err := Init(Config{DryRun: true, Level: "info", Redact: RedactConfig{Patterns: []string{`hunter\d`}}}); err != nil {
+		t.Fatal(err)
+	}
+	entries, unsubscribe := Subscribe(func(x *Entry) bool { return x.Message != "startup" })
+	defer unsubscribe()
+	Info().Str("user", "sloan").Msg("login by {user} with password hunter2")
+	select {
+	case entry := <-entries:
+		if strings.Contains(string(entry.Raw), "hunter2") || !strings.Contains(entry.Message, REDACT_MASK) {
+			t.Fatalf("message is not redacted: %s", entry.Raw)
+		}
+	default:
+		t.Fatal("no entry")
+	}
+}
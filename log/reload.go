@@ -0,0 +1,47 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"os"
+	"time"
+)
+
+// WatchConfig polls the config file and applies it whenever it changes, a config that
+// fails to load or build is logged and the running one is kept; call the returned func to stop
+func WatchConfig(path string, interval time.Duration) func() {
+	done := make(chan struct{})
+	// NOTE taken before returning, a change made right after WatchConfig would otherwise be missed
+	last, _ := os.Stat(path)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || (last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size()) {
+				continue
+			}
+			last = info
+
+			cfg, err := LoadConfig(path)
+			if err == nil {
+				cfg, err = ConfigFromEnv(cfg)
+			}
+			if err == nil {
+				err = apply(cfg)
+			}
+			if err != nil {
+				Error().Err(err).Str("file", path).Msg("logging config reload failed")
+				continue
+			}
+			Info().Str("file", path).Str("log_level", levelName(std.mask())).Msg("logging config reloaded")
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,56 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitFor returns the first entry with message, skipping others, or fails after a second
+func waitFor(t *testing.T, entries <-chan Entry, message string) Entry {
+	t.Helper()
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case entry := <-entries:
+			if entry.Message == message {
+				return entry
+			}
+		case <-deadline:
+			t.Fatalf("no %q entry", message)
+		}
+	}
+}
+
+func TestWatchConfigReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sloan.json")
+	if err := os.WriteFile(path, []byte(`{"level":"warn","dry_run":true}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitFromFile(path); err != nil {
+		t.Fatal(err)
+	}
+	entries, unsubscribe := Subscribe(nil)
+	defer unsubscribe()
+	stop := WatchConfig(path, 5*time.Millisecond)
+	defer stop()
+
+	if err := os.WriteFile(path, []byte(`{"level":"debug","dry_run":true}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if entry := waitFor(t, entries, "logging config reloaded"); entry.Fields["log_level"] != "debug" {
+		t.Fatalf("reload entry: %s", entry.Raw)
+	}
+	Debug().Msg("after reload")
+	waitFor(t, entries, "after reload")
+
+	// NOTE a broken file is reported and the running config stays
+	if err := os.WriteFile(path, []byte(`{"level":`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, entries, "logging config reload failed")
+	Debug().Msg("still debug")
+	waitFor(t, entries, "still debug")
+}
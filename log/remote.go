@@ -0,0 +1,108 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Override is the part of the config a remote endpoint may change for a service
+type Override struct {
+	Level    string          `json:"level,omitempty"`
+	Sampling *SamplingConfig `json:"sampling,omitempty"`
+}
+
+var remote atomic.Pointer[Override]
+
+func remoteOverride() *Override {
+	return remote.Load()
+}
+
+func (x *Override) merge(cfg Config) Config {
+	if x == nil {
+		return cfg
+	}
+	if x.Level != "" {
+		cfg.Level = x.Level
+	}
+	if x.Sampling != nil {
+		cfg.Sampling = *x.Sampling
+	}
+	return cfg
+}
+
+// PollRemote fetches url every interval and applies the returned Override on top of the
+// local config, "{service}" in url is replaced by service so one endpoint can serve a fleet
+// (e.g. consul "http://consul:8500/v1/kv/sloan/{service}?raw"), a 404 clears the override
+func PollRemote(url, service string, interval time.Duration) func() {
+	url = strings.ReplaceAll(url, "{service}", service)
+	client := &http.Client{Timeout: interval}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last []byte
+		for {
+			body, err := fetchOverride(client, url)
+			if err != nil {
+				Warn().Err(err).Str("url", url).Msg("remote logging config poll failed")
+			} else if !bytes.Equal(body, last) {
+				if err := applyOverride(body); err != nil {
+					Error().Err(err).Str("url", url).Msg("remote logging config rejected")
+				} else {
+					Info().Str("url", url).Str("service", service).Msg("remote logging config applied")
+				}
+				last = body
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func fetchOverride(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return []byte{}, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func applyOverride(body []byte) error {
+	if std.current.Load() == nil {
+		return fmt.Errorf("remote overrides require Init, InitFromFile or InitFromEnv")
+	}
+	var next *Override
+	if len(bytes.TrimSpace(body)) > 0 {
+		next = &Override{}
+		if err := json.Unmarshal(body, next); err != nil {
+			return err
+		}
+	}
+	prev := remote.Swap(next)
+	if err := apply(active().source); err != nil {
+		remote.Store(prev)
+		return err
+	}
+	return nil
+}
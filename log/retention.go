@@ -0,0 +1,133 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RetentionConfig purges rotated files last written more than Days ago, the live file and the
+// audit log are never purged; Action is "delete" or "anonymize", which rewrites the file in place
+// with secrets masked by the redact rules and IP addresses hashed
+type RetentionConfig struct {
+	Days   int    `json:"days" yaml:"days" toml:"days"`
+	Action string `json:"action" yaml:"action" toml:"action"`
+}
+
+// Purge applies the retention policy once and records every purged file with Audit
+func Purge() error {
+	st := active()
+	cfg := st.config.Retention
+	if cfg.Days <= 0 {
+		return nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -cfg.Days)
+
+	var anonymizer *Anonymizer
+	if cfg.Action == "anonymize" {
+		salt := make([]byte, 16)
+		rand.Read(salt)
+		var err error
+		if anonymizer, err = NewAnonymizer(st.config.Redact, true, hex.EncodeToString(salt)); err != nil {
+			return err
+		}
+	}
+
+	errs := []error{}
+	for _, fh := range st.files() {
+		if st.audit != nil && st.audit.w == fh {
+			continue
+		}
+		// NOTE collect first, higher numbers are older so purging never leaves a gap before a kept file
+		names := []string{}
+		for i := 1; exists(fh.backup(i)); i++ {
+			names = append(names, fh.backup(i))
+		}
+		for _, name := range names {
+			info, err := os.Stat(name)
+			if err != nil || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			if anonymizer != nil {
+				// NOTE the content changes on purpose, the audit entry replaces the stale HMAC
+				err = errors.Join(anonymizeFile(anonymizer, name), removeIfExists(HMACPath(name)))
+			} else {
+				err = errors.Join(os.Remove(name), removeIfExists(IndexPath(name)), removeIfExists(HMACPath(name)))
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("purge %s: %w", name, err))
+				continue
+			}
+			Audit("sloan", "retention."+cfg.Action, name).Str("modified", info.ModTime().Format(time.RFC3339)).Int("days", cfg.Days).Msg("log file purged by retention policy")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EnforceRetention runs Purge now and then every interval, failures are logged; call the
+// returned func to stop
+func EnforceRetention(interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := Purge(); err != nil {
+				diagnose("purge", "", err)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// anonymizeFile drops lines that are not JSON since they cannot be anonymized field by field, the
+// result keeps the modification time so the file still ages out instead of being anonymized again
+func anonymizeFile(anonymizer *Anonymizer, name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(name+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		entry, err := ParseEntry(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		w.Write(anonymizer.Entry(entry).Raw)
+		w.WriteString("\n")
+	}
+	err = errors.Join(scanner.Err(), w.Flush(), out.Sync(), out.Close(), os.Chtimes(name+".tmp", time.Time{}, info.ModTime()))
+	if err != nil {
+		os.Remove(name + ".tmp")
+		return err
+	}
+	return os.Rename(name+".tmp", name)
+}
+
+func removeIfExists(name string) error {
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
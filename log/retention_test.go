@@ -0,0 +1,88 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// retained starts a file sink at dir/a.log with a recent first backup and a second backup
+// written days ago, and returns the path of the live file
+func retained(t *testing.T, retention RetentionConfig, days int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "a.log")
+	if err := Init(Config{Level: "info", Sinks: []SinkConfig{{Type: "file", Path: path}}, Redact: RedactConfig{Keys: []string{"password"}}, Retention: retention}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Init(Config{DryRun: true}) })
+	old := time.Now().AddDate(0, 0, -days)
+	for name, content := range map[string]string{
+		backupName(path, 1): `{"level":"info","message":"recent"}` + "\n",
+		backupName(path, 2): `{"level":"info","message":"login","client_ip":"10.1.2.3","password":"hunter2"}` + "\nnot json\n",
+	} {
+		if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(backupName(path, 2), old, old); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPurgeDeletesExpiredBackups(t *testing.T) {
+	path := retained(t, RetentionConfig{Days: 7, Action: "delete"}, 10)
+	if err := Purge(); err != nil {
+		t.Fatal(err)
+	}
+	if exists(backupName(path, 2)) {
+		t.Fatal("expired backup kept")
+	}
+	if !exists(backupName(path, 1)) || !exists(path) {
+		t.Fatal("purged a file inside the retention period")
+	}
+}
+
+func TestPurgeAnonymizesExpiredBackups(t *testing.T) {
+	path := retained(t, RetentionConfig{Days: 7, Action: "anonymize"}, 10)
+	name := backupName(path, 2)
+	before, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Purge(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if strings.Contains(text, "hunter2") || strings.Contains(text, "10.1.2.3") || strings.Contains(text, "not json") || !strings.Contains(text, `"message":"login"`) {
+		t.Fatalf("anonymized file: %s", text)
+	}
+	// NOTE the file keeps its age so it is still recognized as expired
+	after, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.ModTime().Equal(before.ModTime()) {
+		t.Fatalf("modification time changed from %s to %s", before.ModTime(), after.ModTime())
+	}
+	if recent, _ := os.ReadFile(backupName(path, 1)); !strings.Contains(string(recent), "recent") {
+		t.Fatalf("anonymized a file inside the retention period: %s", recent)
+	}
+}
+
+func TestPurgeDisabled(t *testing.T) {
+	path := retained(t, RetentionConfig{}, 400)
+	if err := Purge(); err != nil {
+		t.Fatal(err)
+	}
+	if !exists(backupName(path, 2)) {
+		t.Fatal("purged without a retention policy")
+	}
+}
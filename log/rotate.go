@@ -0,0 +1,451 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"compress/gzip"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RotatingFile is an append-only file that rolls over to path.1 .. path.N once it exceeds MaxSize
+// bytes or crosses an Every boundary; backups older than MaxAge are removed after each rotation
+// and Compress gzips them to path.N.gz
+type RotatingFile struct {
+	Path       string
+	MaxSize    int64
+	MaxBackups int
+	MaxAge     time.Duration
+	Every      time.Duration
+	Compress   bool
+	mu         sync.Mutex
+	due        time.Time
+	fh         *os.File
+	size       int64
+	index      *os.File
+	indexEvery int64
+	indexNext  int64
+	chain      bool
+	prev       []byte
+	signer     ed25519.PrivateKey
+	aead       cipher.AEAD
+	keyID      string
+	worm       bool
+	hmacKey    []byte
+	hmacID     string
+	closed     bool
+	failed     time.Time
+}
+
+// NOTE after a failed rotation the live file takes the writes and Write tries again once
+// ROTATE_RETRY has passed, instead of on every line
+const ROTATE_RETRY = time.Minute
+
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file sink requires a path")
+	}
+	x := &RotatingFile{Path: path, MaxSize: maxSize, MaxBackups: maxBackups}
+	if err := x.open(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(x.Path), 0700); err != nil {
+		return err
+	}
+	if x.chain {
+		prev, err := x.continueChain()
+		if err != nil {
+			return err
+		}
+		x.prev = prev
+	}
+	fh, err := os.OpenFile(x.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := fh.Stat()
+	if err != nil {
+		fh.Close()
+		return err
+	}
+	x.fh = fh
+	x.closed = false
+	x.size = info.Size()
+	x.due = time.Time{}
+	if err := x.startEncryption(); err != nil {
+		return err
+	}
+	if x.indexEvery > 0 {
+		if x.index, err = os.OpenFile(IndexPath(x.Path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+			return err
+		}
+		x.indexNext = x.size
+	}
+	return nil
+}
+
+// continueChain reads the chain the live file ends with; a file that has none, a plain one or
+// one whose last line a crash tore, is moved aside to path.broken-<time> with its index and the
+// new file starts a new chain, the moved one still verifies up to where it broke
+func (x *RotatingFile) continueChain() ([]byte, error) {
+	prev, err := lastChain(x.Path)
+	if !errors.Is(err, errChainBroken) {
+		return prev, err
+	}
+	aside := fmt.Sprintf("%s.broken-%s", x.Path, time.Now().UTC().Format("20060102T150405.000"))
+	if err := os.Rename(x.Path, aside); err != nil {
+		return nil, err
+	}
+	if exists(IndexPath(x.Path)) {
+		os.Rename(IndexPath(x.Path), IndexPath(aside))
+	}
+	diagnose("chain", x.Path, fmt.Errorf("%w, moved to %s", err, aside))
+	return make([]byte, sha256.Size), nil
+}
+
+// EnableChain appends a hash of the previous line to every line, see VerifyChain, an existing
+// file continues the chain of its last line and every rotated file starts a new chain
+func (x *RotatingFile) EnableChain() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.chain = true
+	// NOTE reopened so a file that cannot be continued is moved aside before it is written to
+	x.fh.Close()
+	if x.index != nil {
+		x.index.Close()
+	}
+	x.closed = true
+	return x.open()
+}
+
+// EnableSigning chains every line and signs each chain with key so VerifyChain can check the origin
+func (x *RotatingFile) EnableSigning(key ed25519.PrivateKey) error {
+	if err := x.EnableChain(); err != nil {
+		return err
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.signer = key
+	return nil
+}
+
+// EnableEncryption writes every line as an AES-GCM record with the key from the KeyProvider,
+// read the file back with OpenLog
+func (x *RotatingFile) EnableEncryption(keyID string) error {
+	aead, err := newAEAD(keyID)
+	if err != nil {
+		return err
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.aead = aead
+	x.keyID = keyID
+	return x.startEncryption()
+}
+
+// startEncryption writes the header to a new file or checks the one already there
+func (x *RotatingFile) startEncryption() error {
+	if x.aead == nil {
+		return nil
+	}
+	if x.size > 0 {
+		return checkHeader(x.Path, x.keyID)
+	}
+	n, err := x.fh.Write(encryptHeader(x.keyID))
+	x.size += int64(n)
+	return err
+}
+
+// EnableIndex records the time and offset in IndexPath every `every` bytes so OpenIndexed can seek
+func (x *RotatingFile) EnableIndex(every int64) error {
+	x.indexEvery = every
+	x.indexNext = x.size
+	var err error
+	x.index, err = os.OpenFile(IndexPath(x.Path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	return err
+}
+
+// Write expects one complete line per call, which is how the logger writes
+func (x *RotatingFile) Write(p []byte) (int, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.size > 0 && ((x.MaxSize > 0 && x.size+int64(len(p)) > x.MaxSize) || x.overdue()) && time.Since(x.failed) >= ROTATE_RETRY {
+		if err := x.rotate(); err != nil && x.closed {
+			return 0, err
+		}
+	}
+	if x.index != nil && x.size >= x.indexNext {
+		writeIndex(x.index, time.Now(), x.size)
+		x.indexNext = x.size + x.indexEvery
+	}
+	line := p
+	if x.chain {
+		line, x.prev = appendChain(p, x.prev, x.signer)
+	}
+	if x.aead != nil {
+		line = sealRecord(x.aead, x.keyID, line)
+	}
+	n, err := x.fh.Write(line)
+	x.size += int64(n)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// overdue reports whether the live file crossed its Every boundary, boundaries are aligned to
+// UTC so "24h" rotates at midnight
+func (x *RotatingFile) overdue() bool {
+	if x.Every <= 0 {
+		return false
+	}
+	now := time.Now()
+	if x.due.IsZero() {
+		x.due = now.Truncate(x.Every).Add(x.Every)
+		return false
+	}
+	return !now.Before(x.due)
+}
+
+// Rotate shifts path.N-1 to path.N, moves the live file to path.1 and starts a new one
+func (x *RotatingFile) Rotate() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.rotate()
+}
+
+// rotateWritten rotates unless the live file is empty, an empty file would push out a backup
+func (x *RotatingFile) rotateWritten() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.size == 0 || (x.aead != nil && x.size == int64(len(encryptHeader(x.keyID)))) {
+		return nil
+	}
+	return x.rotate()
+}
+
+// rotate moves the live file away and opens a new one; when moving fails the live file is opened
+// again so the sink keeps writing, and the error goes to the diagnostics
+func (x *RotatingFile) rotate() error {
+	x.fh.Close()
+	x.closed = true
+	if x.index != nil {
+		x.index.Close()
+	}
+	err := x.moveLive()
+	x.failed = time.Time{}
+	if err != nil {
+		x.failed = time.Now()
+	}
+	if reopen := x.open(); reopen != nil {
+		err = errors.Join(err, reopen)
+	}
+	if err != nil {
+		diagnose("rotate", x.Path, err)
+	}
+	return err
+}
+
+// moveLive seals the closed live file and moves it to the first backup
+func (x *RotatingFile) moveLive() error {
+	seal := ""
+	if x.hmacKey != nil {
+		var err error
+		if seal, err = fileHMAC(x.Path, x.hmacKey); err != nil {
+			return err
+		}
+	}
+	if x.worm {
+		if err := x.rotateWORM(); err != nil {
+			return err
+		}
+	} else if x.Compress {
+		if err := x.rotateCompressed(); err != nil {
+			return err
+		}
+	} else {
+		shift(x.MaxBackups, x.backup)
+		for _, sidecar := range x.sidecars() {
+			shift(x.MaxBackups, func(i int) string { return sidecar(x.backup(i)) })
+		}
+	}
+	if !x.worm && x.MaxAge > 0 {
+		x.prune()
+	}
+	if x.hmacKey != nil {
+		if err := writeHMAC(x.backup(1), x.hmacID, seal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sidecars name the files that move along with each rotated file
+func (x *RotatingFile) sidecars() []func(string) string {
+	names := []func(string) string{}
+	if x.index != nil {
+		names = append(names, IndexPath)
+	}
+	if x.hmacKey != nil {
+		names = append(names, HMACPath)
+	}
+	return names
+}
+
+// backup names the i-th rotated file, 0 is the live file
+func (x *RotatingFile) backup(i int) string {
+	if x.Compress && i > 0 {
+		return backupName(x.Path, i) + ".gz"
+	}
+	return backupName(x.Path, i)
+}
+
+// rotateCompressed shifts the gzipped backups and compresses the live file into path.1.gz, the
+// writer waits for it, which bounds the cost to one file of MaxSize
+func (x *RotatingFile) rotateCompressed() error {
+	backups := x.MaxBackups
+	if backups <= 0 {
+		backups = 1
+	}
+	os.Remove(x.backup(backups))
+	for i := backups - 1; i > 0; i-- {
+		os.Rename(x.backup(i), x.backup(i+1))
+	}
+	return gzipFile(x.Path, x.backup(1))
+}
+
+// gzipFile writes src compressed to dst and removes src, dst only appears once complete
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if err == nil {
+		err = zw.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(dst+".tmp", dst)
+	}
+	if err != nil {
+		os.Remove(dst + ".tmp")
+		return err
+	}
+	return os.Remove(src)
+}
+
+// prune removes the backups last modified before MaxAge, higher numbers are older so no gap is left
+func (x *RotatingFile) prune() {
+	cutoff := time.Now().Add(-x.MaxAge)
+	for i := 1; exists(x.backup(i)); i++ {
+		info, err := os.Stat(x.backup(i))
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		os.Remove(x.backup(i))
+		for _, sidecar := range x.sidecars() {
+			os.Remove(sidecar(x.backup(i)))
+		}
+	}
+}
+
+func backupName(path string, i int) string {
+	if i == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// NOTE with no backups configured the previous file is still kept as path.1
+func shift(backups int, name func(int) string) {
+	if backups <= 0 {
+		backups = 1
+	}
+	os.Remove(name(backups))
+	for i := backups - 1; i >= 0; i-- {
+		os.Rename(name(i), name(i+1))
+	}
+}
+
+// Reopen closes the live file and opens Path again, after an external logrotate moved it away
+// the logger would otherwise keep writing to the moved file
+func (x *RotatingFile) Reopen() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.fh.Close()
+	if x.index != nil {
+		x.index.Close()
+	}
+	x.closed = true
+	return x.open()
+}
+
+// Seek moves the offset of the live file, WORM files refuse with ErrAppendOnly
+func (x *RotatingFile) Seek(offset int64, whence int) (int64, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.worm {
+		return 0, ErrAppendOnly
+	}
+	return x.fh.Seek(offset, whence)
+}
+
+// Truncate cuts the live file to size, WORM files refuse with ErrAppendOnly
+func (x *RotatingFile) Truncate(size int64) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.worm {
+		return ErrAppendOnly
+	}
+	if err := x.fh.Truncate(size); err != nil {
+		return err
+	}
+	x.size = size
+	return nil
+}
+
+// Sync commits the file and its index to stable storage
+func (x *RotatingFile) Sync() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.index != nil {
+		x.index.Sync()
+	}
+	return x.fh.Sync()
+}
+
+func (x *RotatingFile) Close() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.index != nil {
+		x.index.Close()
+	}
+	x.closed = true
+	return x.fh.Close()
+}
+
+func (x *RotatingFile) isClosed() bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.closed
+}
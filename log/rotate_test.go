@@ -0,0 +1,41 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotateFailureKeepsWriting(t *testing.T) {
+	quietDiagnostics(t)
+	path := filepath.Join(t.TempDir(), "a.log")
+	fh, err := NewRotatingFile(path, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	fh.Compress = true
+	// NOTE a directory in the place of the backup makes the move fail
+	if err := os.MkdirAll(filepath.Join(fh.backup(1), "x"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fh.Write([]byte(`{"message":"one"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fh.Rotate(); err == nil {
+		t.Fatal("expected the rotation to fail")
+	}
+	if _, err := fh.Write([]byte(`{"message":"two"}` + "\n")); err != nil {
+		t.Fatalf("sink died with the rotation: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "two") {
+		t.Fatalf("live file: %q %v", data, err)
+	}
+	diagnostics := Diagnostics()
+	if last := diagnostics[len(diagnostics)-1]; last.Op != "rotate" || last.Target != path {
+		t.Fatalf("rotation failure not diagnosed: %+v", last)
+	}
+}
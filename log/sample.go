@@ -0,0 +1,248 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingConfig keeps 1 of every Every events at the listed levels, Every <= 1 keeps all; Burst
+// lets that many entries with the same level, component and message through per Period ("1s" by
+// default) at any level but fatal, the rest is counted in a "suppressed N duplicates" entry
+// written when the period ends, or on Flush and Close
+type SamplingConfig struct {
+	Every  int      `json:"every" yaml:"every" toml:"every"`
+	Levels []string `json:"levels" yaml:"levels" toml:"levels"`
+	Burst  int      `json:"burst" yaml:"burst" toml:"burst"`
+	Period string   `json:"period" yaml:"period" toml:"period"`
+}
+
+type sampler struct {
+	every  uint64
+	levels map[int]*atomic.Uint64
+	limit  *limiter
+}
+
+func newSampler(cfg SamplingConfig) (*sampler, error) {
+	if cfg.Every <= 1 && cfg.Burst <= 0 {
+		return nil, nil
+	}
+	x := &sampler{}
+	if cfg.Every > 1 {
+		levels := cfg.Levels
+		if len(levels) == 0 {
+			levels = []string{"trace", "debug", "info"}
+		}
+		x.every, x.levels = uint64(cfg.Every), map[int]*atomic.Uint64{}
+		for _, name := range levels {
+			level, ok := levelOf(name)
+			if !ok {
+				return nil, fmt.Errorf("sampling: unknown log level %q", name)
+			}
+			x.levels[level] = &atomic.Uint64{}
+		}
+	}
+	if cfg.Burst > 0 {
+		period := time.Second
+		if cfg.Period != "" {
+			var err error
+			if period, err = time.ParseDuration(cfg.Period); err != nil {
+				return nil, fmt.Errorf("sampling: %w", err)
+			}
+		}
+		x.limit = newLimiter(cfg.Burst, period)
+	}
+	return x, nil
+}
+
+// keep applies 1 in every, then the rate limit keyed by the message before its placeholders are filled
+func (x *sampler) keep(e *Event, template string) bool {
+	if x == nil || e.summary || e.terminate != "" {
+		return true
+	}
+	if count, ok := x.levels[e.level]; ok && (count.Add(1)-1)%x.every != 0 {
+		return false
+	}
+	if x.limit == nil {
+		return true
+	}
+	return x.limit.allow(limitKey{level: e.level, component: e.component, msg: template}, e.logger, time.Now())
+}
+
+// flush writes the counts of the period so far, before a Flush or before the sinks are closed
+func (x *sampler) flush() {
+	if x != nil {
+		x.limit.flush()
+	}
+}
+
+// sampled checks the sampler of the config, then the one of a logger made by Sample or RateLimit
+func (x *Event) sampled(sample *sampler, template string) bool {
+	return sample.keep(x, template) && x.logger.sample.keep(x, template)
+}
+
+type limitKey struct {
+	level     int
+	component string
+	msg       string
+}
+
+// limitCount is how often a message was seen in the period and the logger its summary goes to
+type limitCount struct {
+	count  int
+	logger *Logger
+}
+
+// limitSummary is the count of a message that was suppressed
+type limitSummary struct {
+	key    limitKey
+	count  int
+	logger *Logger
+}
+
+// NOTE the counts are reset every period, so only the messages of one period are kept in memory;
+// the timer runs while a period has suppressed entries of a logger, to write their summaries
+type limiter struct {
+	burst  int
+	period time.Duration
+	mu     sync.Mutex
+	start  time.Time
+	seen   map[limitKey]*limitCount
+	timer  *time.Timer
+}
+
+func newLimiter(burst int, period time.Duration) *limiter {
+	return &limiter{burst: burst, period: period, seen: map[limitKey]*limitCount{}}
+}
+
+// allow counts the entry, the suppressed ones are summarized to logger when it is not nil
+func (x *limiter) allow(key limitKey, logger *Logger, now time.Time) bool {
+	x.mu.Lock()
+	var ended []limitSummary
+	if now.Sub(x.start) >= x.period {
+		ended = x.end(true)
+		x.start = now
+	}
+	item := x.seen[key]
+	if item == nil {
+		item = &limitCount{logger: logger}
+		x.seen[key] = item
+	}
+	item.count++
+	keep := item.count <= x.burst
+	if !keep && logger != nil && x.timer == nil {
+		start := x.start
+		x.timer = time.AfterFunc(start.Add(x.period).Sub(now), func() { x.expire(start) })
+	}
+	x.mu.Unlock()
+	writeSummaries(ended)
+	return keep
+}
+
+// expire ends the period that began at start unless an entry already started the next one
+func (x *limiter) expire(start time.Time) {
+	x.mu.Lock()
+	if !x.start.Equal(start) {
+		x.mu.Unlock()
+		return
+	}
+	ended := x.end(true)
+	x.start = time.Time{}
+	x.mu.Unlock()
+	writeSummaries(ended)
+}
+
+// flush takes the counts suppressed so far out of the period without ending it
+func (x *limiter) flush() {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	ended := x.end(false)
+	x.mu.Unlock()
+	writeSummaries(ended)
+}
+
+// end collects the suppressed counts, reset starts the next period and otherwise the messages
+// stay at their burst so they keep being limited
+func (x *limiter) end(reset bool) []limitSummary {
+	var ended []limitSummary
+	for key, item := range x.seen {
+		if item.count > x.burst {
+			if item.logger != nil {
+				ended = append(ended, limitSummary{key: key, count: item.count - x.burst, logger: item.logger})
+			}
+			item.count = x.burst
+		}
+	}
+	if reset {
+		x.seen = map[limitKey]*limitCount{}
+	}
+	if x.timer != nil {
+		x.timer.Stop()
+		x.timer = nil
+	}
+	return ended
+}
+
+func writeSummaries(ended []limitSummary) {
+	for _, item := range ended {
+		item.logger.summarize(item.key, item.count)
+	}
+}
+
+// summarize writes how many entries of a message were dropped, at the level they had
+func (x *Logger) summarize(key limitKey, count int) {
+	logger := x.leveled(key.level, eventName(key.level, false))
+	e := logger.(*Event)
+	if e.ignore {
+		return
+	}
+	e.summary = true
+	if key.component != x.component {
+		// NOTE the fields of the logger already carry a component, a second key would be ambiguous
+		if x.component == "" {
+			e.Str("component", key.component)
+		} else {
+			e.component = key.component
+		}
+	}
+	e.Int("suppressed", count).Str("duplicate", key.msg).Msg("suppressed {suppressed} duplicates")
+}
+
+// Sample returns a child logger that writes 1 of every n entries at any level but fatal
+func (x *Logger) Sample(n int) *Logger {
+	child := x.With().Logger()
+	child.sample = x.sample.with(func(next *sampler) {
+		next.every, next.levels = uint64(n), map[int]*atomic.Uint64{}
+		for _, level := range []int{LOG_TRACE, LOG_DEBUG, LOG_INFO, LOG_WARN, LOG_ERROR} {
+			next.levels[level] = &atomic.Uint64{}
+		}
+	})
+	if n <= 1 {
+		child.sample = x.sample
+	}
+	return child
+}
+
+// RateLimit returns a child logger that writes burst entries of the same message per period, the
+// others are counted in a "suppressed N duplicates" entry written when the period ends
+func (x *Logger) RateLimit(burst int, period time.Duration) *Logger {
+	child := x.With().Logger()
+	child.sample = x.sample.with(func(next *sampler) {
+		next.limit = newLimiter(burst, period)
+	})
+	return child
+}
+
+// with copies the sampler of a parent logger, so Sample and RateLimit can be combined
+func (x *sampler) with(change func(*sampler)) *sampler {
+	next := &sampler{}
+	if x != nil {
+		*next = *x
+	}
+	change(next)
+	return next
+}
@@ -0,0 +1,78 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampleEvery(t *testing.T) {
+	entries := capture(t, Config{Level: "debug", Sampling: SamplingConfig{Every: 3, Levels: []string{"debug"}}})
+	for i := 0; i < 6; i++ {
+		Debug().Int("i", i).Msg("tick")
+	}
+	Info().Msg("not sampled")
+	for _, want := range []string{"0", "3"} {
+		entry := received(t, entries)
+		if i, _ := entry.Field("i"); i != want {
+			t.Fatalf("expected i=%s, got %s", want, entry.Raw)
+		}
+	}
+	if entry := received(t, entries); entry.Message != "not sampled" {
+		t.Fatalf("info was sampled: %s", entry.Raw)
+	}
+}
+
+func TestRateLimitSummaryWhenPeriodEnds(t *testing.T) {
+	entries := capture(t, Config{Level: "info", Sampling: SamplingConfig{Burst: 2, Period: "50ms"}})
+	for i := 0; i < 5; i++ {
+		Info().Msg("busy")
+	}
+	received(t, entries)
+	received(t, entries)
+	// NOTE no entry follows, the period ending on its own writes the summary
+	entry := received(t, entries)
+	if suppressed, _ := entry.Field("suppressed"); suppressed != "3" || entry.Fields["duplicate"] != "busy" {
+		t.Fatalf("summary: %s", entry.Raw)
+	}
+	Info().Msg("busy")
+	if entry := received(t, entries); entry.Message != "busy" {
+		t.Fatalf("next period: %s", entry.Raw)
+	}
+}
+
+func TestRateLimitSummaryOnFlush(t *testing.T) {
+	entries := capture(t, Config{Level: "info", Sampling: SamplingConfig{Burst: 1, Period: "1m"}})
+	Info().Msg("busy")
+	Info().Msg("busy")
+	received(t, entries)
+	if err := Flush(); err != nil {
+		t.Fatal(err)
+	}
+	entry := received(t, entries)
+	if suppressed, _ := entry.Field("suppressed"); suppressed != "1" {
+		t.Fatalf("summary: %s", entry.Raw)
+	}
+	// NOTE the period goes on, the message stays limited
+	Info().Msg("busy")
+	quiet(t, entries, 20*time.Millisecond)
+}
+
+func TestRateLimitChildWritesOneComponent(t *testing.T) {
+	entries := capture(t, Config{Level: "info"})
+	logger := Default().Component("dns").RateLimit(1, time.Minute)
+	logger.Info().Msg("lookup failed")
+	logger.Info().Msg("lookup failed")
+	received(t, entries)
+	if err := logger.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	entry := received(t, entries)
+	if strings.Count(string(entry.Raw), `"component"`) != 1 {
+		t.Fatalf("summary: %s", entry.Raw)
+	}
+	if component, _ := entry.Field("component"); component != "dns" {
+		t.Fatalf("summary component: %s", entry.Raw)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// HandleSignals waits for SIGTERM or SIGINT, logs it, flushes and rotates every file sink that
+// was written to so the last file is complete and sealed, closes every sink and then calls
+// shutdown, which must not log; canceling ctx stops waiting and restores the default handling
+func HandleSignals(ctx context.Context, shutdown func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		defer signal.Stop(ch)
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-ch:
+			Info().Str("component", "osintami").Str("signal", sig.String()).Msg("shutting down")
+		}
+		if err := Flush(); err != nil {
+			diagnose("flush", "", err)
+		}
+		for _, fh := range active().files() {
+			// NOTE a failed rotation reports itself to the diagnostics
+			fh.rotateWritten()
+		}
+		Shutdown()
+		if shutdown != nil {
+			shutdown()
+		}
+	}()
+}
+
+// ReopenOnSignal calls Reopen on every SIGHUP, which is what logrotate sends from postrotate,
+// call the returned func to restore the default
+func ReopenOnSignal() func() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				if err := Reopen(); err != nil {
+					diagnose("reopen", "", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
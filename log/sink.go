@@ -0,0 +1,437 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type sink struct {
+	name    string
+	level   int
+	w       io.Writer
+	format  string
+	encoder Encoder // nil writes the JSON line as built
+	queue   *asyncQueue
+	key     string // of a file sink, see fileKey
+
+	// borrowed is set on a file taken over from the running config until the new one is swapped
+	// in, closing the sinks of a config that fails to build leaves it open
+	borrowed bool
+
+	failing   atomic.Bool
+	bytes     atomic.Uint64 // written, see Metrics.SinkBytes
+	dropped   atomic.Uint64 // discarded by the async queue, see Metrics.SinkDropped
+	mu        sync.Mutex
+	lastError error
+	errorAt   time.Time
+	flushedAt time.Time
+}
+
+func openSinks(configs []SinkConfig, rotation RotationConfig, dryRun bool, files map[string]*RotatingFile) ([]*sink, error) {
+	if len(configs) == 0 {
+		configs = []SinkConfig{{Type: "stderr"}}
+	}
+	sinks := []*sink{}
+	for _, cfg := range configs {
+		x, err := openSink(cfg, rotation, dryRun, files)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, err
+		}
+		sinks = append(sinks, x)
+	}
+	return sinks, nil
+}
+
+// indent falls back to the line as is when it is not valid JSON
+func indent(out []byte) []byte {
+	var buffer bytes.Buffer
+	if err := json.Indent(&buffer, out, "", "  "); err != nil {
+		return out
+	}
+	return buffer.Bytes()
+}
+
+// fileKey tells file sinks apart, a reload takes over the file of a sink whose key is unchanged
+func fileKey(cfg SinkConfig, rotation RotationConfig) string {
+	data, _ := json.Marshal([]interface{}{cfg, rotation})
+	return string(data)
+}
+
+// openSink in a dry run records what the sink would get instead of opening it; a file sink takes
+// over the file of files with the same fileKey, so a reload neither reopens it nor starts a second
+// writer on its hash chain
+func openSink(cfg SinkConfig, rotation RotationConfig, dryRun bool, files map[string]*RotatingFile) (*sink, error) {
+	x := &sink{name: cfg.Type, level: LOG_TRACE, format: cfg.Format}
+	if cfg.Level != "" {
+		mask, ok := parseLevel(cfg.Level)
+		if !ok {
+			return nil, fmt.Errorf("sink %s: unknown log level %q", cfg.Type, cfg.Level)
+		}
+		x.level = mask
+	}
+	if dryRun {
+		if cfg.Type == "file" {
+			x.name = cfg.Path
+		}
+		x.w = &dryRunWriter{}
+		x.setFormat(cfg.Format, cfg.Pretty)
+		return x, nil
+	}
+
+	switch cfg.Type {
+	case "stderr":
+		x.w = os.Stderr
+		if JOURNALD_AUTO && (cfg.Format == "" || strings.EqualFold(cfg.Format, "json")) && !cfg.Pretty && journalStream() {
+			if w, err := NewJournaldWriter(); err == nil {
+				x.name, x.w = "journald", w
+			}
+		}
+	case "stdout":
+		x.w = os.Stdout
+	case "file":
+		x.name, x.key = cfg.Path, fileKey(cfg, rotation)
+		if fh, ok := files[x.key]; ok {
+			delete(files, x.key)
+			x.w, x.borrowed = fh, true
+			break
+		}
+		fh, err := NewRotatingFile(cfg.Path, int64(rotation.MaxSizeMB)*1024*1024, rotation.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		// NOTE the interval was checked by Validate
+		fh.Every, _ = time.ParseDuration(rotation.Interval)
+		fh.MaxAge = time.Duration(rotation.MaxAgeDays) * 24 * time.Hour
+		fh.Compress = rotation.Compress
+		if cfg.Index {
+			if err := fh.EnableIndex(INDEX_EVERY); err != nil {
+				fh.Close()
+				return nil, err
+			}
+		}
+		if cfg.Chain {
+			if err := fh.EnableChain(); err != nil {
+				fh.Close()
+				return nil, err
+			}
+		}
+		if cfg.SigningKey != "" {
+			key, err := LoadSigningKey(cfg.SigningKey)
+			if err == nil {
+				err = fh.EnableSigning(key)
+			}
+			if err != nil {
+				fh.Close()
+				return nil, err
+			}
+		}
+		if cfg.Encrypt != "" {
+			if err := fh.EnableEncryption(cfg.Encrypt); err != nil {
+				fh.Close()
+				return nil, err
+			}
+		}
+		if cfg.HMAC != "" {
+			if err := fh.EnableHMAC(cfg.HMAC); err != nil {
+				fh.Close()
+				return nil, err
+			}
+		}
+		if cfg.WORM {
+			fh.EnableWORM()
+		}
+		x.w = fh
+	case "syslog":
+		w, err := NewSyslogWriter(cfg.Path, cfg.Syslog)
+		if err != nil {
+			return nil, err
+		}
+		x.name = strings.TrimSuffix("syslog:"+cfg.Path, ":")
+		x.w = w
+	case "journald":
+		w, err := NewJournaldWriter()
+		if err != nil {
+			return nil, err
+		}
+		x.w = w
+	case "net":
+		// NOTE Validate has checked the URL, tls://host:port is tcp with TLS verified against host
+		target, _ := url.Parse(cfg.Path)
+		network, address, options := target.Scheme, target.Host, NetOptions{}
+		switch network {
+		case "tls":
+			network, options.TLS = "tcp", &tls.Config{ServerName: target.Hostname()}
+		case "unix", "unixgram":
+			address = target.Path
+		}
+		x.name = cfg.Path
+		x.w = NewNetWriter(network, address, options)
+	case "loki":
+		w, err := NewLokiWriter(cfg.Path, cfg.Loki)
+		if err != nil {
+			return nil, err
+		}
+		x.name = cfg.Path
+		x.w = w
+	case "elastic":
+		w, err := NewElasticWriter(cfg.Path, cfg.Elastic)
+		if err != nil {
+			return nil, err
+		}
+		// NOTE the name shows up in metrics and diagnostics, keep the password out of it
+		target, _ := url.Parse(cfg.Path)
+		x.name = target.Redacted()
+		x.w = w
+	case "webhook":
+		w, err := NewWebhookWriter(cfg.Path, cfg.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		// NOTE webhook paths often carry the token (Slack, Discord), only the host is shown
+		target, _ := url.Parse(cfg.Path)
+		x.name = target.Scheme + "://" + target.Host
+		x.w = w
+	case "otlp":
+		w, err := NewOTLPWriter(cfg.Path, cfg.OTLP)
+		if err != nil {
+			return nil, err
+		}
+		x.name = cfg.Path
+		x.w = w
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+	x.setFormat(cfg.Format, cfg.Pretty)
+	return x, nil
+}
+
+// setFormat picks the encoder of the sink, Validate has checked the format
+func (x *sink) setFormat(format string, pretty bool) {
+	if fn, ok := encoders[strings.ToLower(format)]; ok {
+		x.encoder = fn(x.w)
+	}
+	if x.encoder == nil && pretty {
+		x.encoder = EncoderFunc(indent)
+	}
+}
+
+func writeSinks(sinks []*sink, level int, out []byte) {
+	for _, x := range sinks {
+		if x.level&level != level {
+			continue
+		}
+		line := out
+		if x.encoder != nil {
+			line = x.encoder.Encode(out)
+		}
+		if x.queue != nil && x.queue.enqueue(line, level, level == LOG_FATAL) {
+			continue
+		}
+		x.write(line, level)
+	}
+}
+
+func (x *sink) write(line []byte, level int) {
+	var err error
+	if w, ok := x.w.(LevelWriter); ok {
+		_, err = w.WriteLevel(levelOfEvent(level), line)
+	} else {
+		_, err = x.w.Write(line)
+	}
+	if err != nil {
+		countSinkError(x.name)
+		x.fail("write", err)
+		return
+	}
+	x.bytes.Add(uint64(len(line)))
+	if x.failing.Load() && x.failing.Swap(false) {
+		diagnose("recover", x.name, nil)
+	}
+}
+
+func closeSinks(sinks []*sink) {
+	closeSinksExcept(sinks, nil)
+}
+
+// closeSinksExcept leaves open the writers in keep, those a new config took over
+func closeSinksExcept(sinks []*sink, keep map[io.Writer]bool) {
+	for _, x := range sinks {
+		if x.queue != nil {
+			x.queue.close()
+		}
+		if x.w == os.Stderr || x.w == os.Stdout || x.borrowed || keep[x.w] {
+			continue
+		}
+		if c, ok := x.w.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+// all returns every sink including the audit and component sinks
+func (x *state) all() []*sink {
+	sinks := append([]*sink{}, x.sinks...)
+	if x.audit != nil {
+		sinks = append(sinks, x.audit)
+	}
+	for _, item := range x.components {
+		sinks = append(sinks, item.sinks...)
+	}
+	return sinks
+}
+
+// files returns every rotating file sink, including component sinks
+func (x *state) files() []*RotatingFile {
+	files := []*RotatingFile{}
+	for _, item := range x.all() {
+		if fh, ok := item.w.(*RotatingFile); ok {
+			files = append(files, fh)
+		}
+	}
+	return files
+}
+
+// Rotate rolls over every file sink of the default logger now
+func Rotate() error {
+	return std.Rotate()
+}
+
+// Reopen opens every file of the default logger again, LOG_FH included
+func Reopen() error {
+	return std.Reopen()
+}
+
+// Flush commits every file of the default logger, LOG_FH included, to stable storage
+func Flush() error {
+	return std.Flush()
+}
+
+// Rotate rolls over every file sink now
+func (x *Logger) Rotate() error {
+	errs := []error{}
+	for _, fh := range x.active().files() {
+		if err := fh.Rotate(); err != nil {
+			errs = append(errs, fmt.Errorf("rotate %s: %w", fh.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Reopen closes and opens every file sink again by path, see ReopenOnSignal
+func (x *Logger) Reopen() error {
+	errs := []error{}
+	if x.legacy && LOG_FH != nil {
+		if err := reopenLegacy(); err != nil {
+			errs = append(errs, fmt.Errorf("reopen %s: %w", LOG_FILE, err))
+		}
+	}
+	for _, fh := range x.active().files() {
+		if err := fh.Reopen(); err != nil {
+			errs = append(errs, fmt.Errorf("reopen %s: %w", fh.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reopenLegacy swaps LOG_FH for a new handle on LOG_FILE, the old one keeps working until then
+func reopenLegacy() error {
+	fh, err := os.OpenFile(LOG_FILE, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	legacyMu.Lock()
+	defer legacyMu.Unlock()
+	old := LOG_FH
+	LOG_FH = fh
+	return old.Close()
+}
+
+// flusher is a writer holding entries of its own in memory, e.g. the batch of a network sink,
+// Flush hands them on before ctx is done
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// flushLoop asks the goroutine of a batching writer to send what it holds and waits for it, a
+// writer that is closed has nothing left to send
+func flushLoop(ctx context.Context, flush chan chan struct{}, stopped chan struct{}, name string) error {
+	reply := make(chan struct{})
+	select {
+	case flush <- reply:
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s writer: flush: %w", name, ctx.Err())
+	}
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s writer: flush: %w", name, ctx.Err())
+	}
+}
+
+// Flush writes what the async queues hold, has network sinks send their batches, waiting up to
+// NET_CLOSE_TIMEOUT, and commits every file sink to stable storage
+func (x *Logger) Flush() error {
+	x.sample.flush()
+	errs := []error{x.active().drain(context.Background())}
+	ctx, cancel := context.WithTimeout(context.Background(), NET_CLOSE_TIMEOUT)
+	defer cancel()
+	for _, item := range x.active().all() {
+		if w, ok := item.w.(flusher); ok {
+			if err := w.Flush(ctx); err != nil {
+				item.fail("flush", err)
+				errs = append(errs, err)
+			}
+		}
+	}
+	if x.legacy {
+		legacyMu.Lock()
+		if LOG_FH != nil {
+			if err := LOG_FH.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		legacyMu.Unlock()
+	}
+	for _, item := range x.active().all() {
+		fh, ok := item.w.(*RotatingFile)
+		if !ok {
+			continue
+		}
+		if err := fh.Sync(); err != nil {
+			item.fail("flush", err)
+			errs = append(errs, fmt.Errorf("flush %s: %w", fh.Path, err))
+			continue
+		}
+		item.mu.Lock()
+		item.flushedAt = time.Now()
+		item.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// fail reports the first error of a failing sink, repeats only update LastError
+func (x *sink) fail(op string, err error) {
+	if !x.failing.Swap(true) {
+		diagnose(op, x.name, err)
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.lastError = err
+	x.errorAt = time.Now()
+}
@@ -0,0 +1,212 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// NOTE slog levels between ours go to the level below, e.g. slog.LevelInfo+2 is info; slog has
+// no fatal so nothing written through it ends the process
+const SLOG_TRACE = slog.LevelDebug - 4
+
+// slogLevel maps a level to slog, fatal and panic are slog.LevelError+4
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case TraceLevel:
+		return SLOG_TRACE
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	}
+	return slog.LevelError + 4
+}
+
+func levelOfSlog(level slog.Level) (int, string) {
+	switch {
+	case level < slog.LevelDebug:
+		return LOG_TRACE, "trace"
+	case level < slog.LevelInfo:
+		return LOG_DEBUG, "debug"
+	case level < slog.LevelWarn:
+		return LOG_INFO, "info"
+	case level < slog.LevelError:
+		return LOG_WARN, "warn"
+	}
+	return LOG_ERROR, "error"
+}
+
+// SlogHandler writes slog records through a Logger, with its levels, sampling, hooks, encoders
+// and sinks; groups become nested objects
+type SlogHandler struct {
+	logger *Logger
+	groups []string
+	nested [][]slog.Attr // attributes added after each group was opened
+}
+
+// NewSlogHandler routes slog through logger, e.g. slog.SetDefault(slog.New(log.NewSlogHandler(log.Default())))
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+func (x *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	mask, _ := levelOfSlog(level)
+	st := x.logger.active()
+	return (x.logger.mask()|x.logger.floor(st))&mask == mask
+}
+
+func (x *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	level, name := levelOfSlog(r.Level)
+	e, ok := x.logger.leveled(level, name).(*Event)
+	if !ok || e.ignore {
+		return nil
+	}
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(x.groups) - 1; i >= 0; i-- {
+		attrs = []slog.Attr{{Key: x.groups[i], Value: slog.GroupValue(append(append([]slog.Attr{}, x.nested[i]...), attrs...)...)}}
+	}
+	for _, a := range attrs {
+		slogAttr(e, a)
+	}
+	if e.st.config.Caller {
+		if r.PC != 0 {
+			e.buf = appendFrame(e.buf, e.st.config, r.PC)
+		}
+		e.caller = true
+	}
+	e.Msg(r.Message)
+	return nil
+}
+
+// WithAttrs outside a group become fields of a child logger, encoded once like With
+func (x *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return x
+	}
+	if len(x.groups) > 0 {
+		nested := append([][]slog.Attr{}, x.nested...)
+		nested[len(nested)-1] = append(append([]slog.Attr{}, nested[len(nested)-1]...), attrs...)
+		return &SlogHandler{logger: x.logger, groups: x.groups, nested: nested}
+	}
+	child := x.logger.With()
+	for _, a := range attrs {
+		slogAttr(child.e, a)
+	}
+	return &SlogHandler{logger: child.Logger()}
+}
+
+func (x *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return x
+	}
+	return &SlogHandler{logger: x.logger, groups: append(append([]string{}, x.groups...), name), nested: append(append([][]slog.Attr{}, x.nested...), nil)}
+}
+
+// slogAttr adds an attribute with the typed field methods, empty groups are left out and
+// groups without a key are inlined as slog asks of handlers
+func slogAttr(e *Event, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		e.Str(a.Key, a.Value.String())
+	case slog.KindInt64:
+		e.Int64(a.Key, a.Value.Int64())
+	case slog.KindUint64:
+		e.Uint64(a.Key, a.Value.Uint64())
+	case slog.KindFloat64:
+		e.Float64(a.Key, a.Value.Float64())
+	case slog.KindBool:
+		e.Bool(a.Key, a.Value.Bool())
+	case slog.KindDuration:
+		e.Dur(a.Key, a.Value.Duration())
+	case slog.KindTime:
+		e.Time(a.Key, a.Value.Time())
+	case slog.KindGroup:
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return
+		}
+		if a.Key == "" {
+			for _, item := range group {
+				slogAttr(e, item)
+			}
+			return
+		}
+		dict := newEvent(e.logger, e.st, LOG_TRACE)
+		for _, item := range group {
+			slogAttr(dict, item)
+		}
+		e.Dict(a.Key, dict)
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			e.Str(a.Key, err.Error())
+			return
+		}
+		e.Any(a.Key, a.Value.Any())
+	}
+}
+
+// FromSlog returns a Logger whose entries are handed to h as slog records, for code moving off
+// this package gradually; its level is the lowest h is enabled for and its fields become attributes
+func FromSlog(h slog.Handler) *Logger {
+	x := &Logger{current: &atomic.Pointer[state]{}, writers: &atomic.Pointer[[]*sink]{}, hooks: &atomic.Pointer[[]Hook]{}, level: newLevel(LOG_FATAL), overrides: &atomic.Pointer[componentLevels]{}}
+	for level := TraceLevel; level < FatalLevel; level++ {
+		if h.Enabled(context.Background(), slogLevel(level)) {
+			x.level.Store(int32(level.mask()))
+			break
+		}
+	}
+	x.AddWriter(&slogWriter{logger: x, handler: h})
+	return x
+}
+
+// slogWriter turns the lines of a Logger back into slog records
+type slogWriter struct {
+	logger  *Logger
+	handler slog.Handler
+}
+
+func (x *slogWriter) Write(p []byte) (int, error) {
+	cfg := x.logger.active().config
+	timeKey, levelKey, messageKey := cfg.timeKey(), cfg.levelKey(), cfg.messageKey()
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		keys, values, ok := orderedFields(line)
+		if !ok {
+			continue
+		}
+		text := func(key string) string {
+			value, _ := decodeValue(values[key]).(string)
+			return value
+		}
+		level, err := ParseLevel(text(levelKey))
+		if err != nil {
+			level = FatalLevel
+		}
+		record := slog.NewRecord(parseTimestamp(decodeValue(values[timeKey]), cfg), slogLevel(level), text(messageKey), 0)
+		for _, key := range keys {
+			if key != timeKey && key != levelKey && key != messageKey {
+				record.AddAttrs(slog.Any(key, decodeValue(values[key])))
+			}
+		}
+		if x.handler.Enabled(context.Background(), record.Level) {
+			if err := x.handler.Handle(context.Background(), record); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,64 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandlerFieldsAndGroups(t *testing.T) {
+	entries := capture(t, Config{Level: "info"})
+	logger := slog.New(NewSlogHandler(Default())).With("service", "api").WithGroup("req").With("id", "r1")
+	logger.Info("served", "status", 200, slog.Group("user", "name", "ana"), slog.Group("empty"))
+
+	entry := received(t, entries)
+	if entry.Message != "served" || entry.Level != "info" || entry.Fields["service"] != "api" {
+		t.Fatalf("entry: %s", entry.Raw)
+	}
+	if req, _ := entry.Field("req"); req != `{"id":"r1","status":200,"user":{"name":"ana"}}` {
+		t.Fatalf("req group: %s", req)
+	}
+}
+
+func TestSlogHandlerLevels(t *testing.T) {
+	entries := capture(t, Config{Level: "warn"})
+	handler := NewSlogHandler(Default())
+	if handler.Enabled(context.Background(), slog.LevelInfo) || !handler.Enabled(context.Background(), slog.LevelWarn) {
+		t.Fatal("enabled does not follow the logger level")
+	}
+	logger := slog.New(handler)
+	logger.Info("dropped")
+	logger.Log(context.Background(), slog.LevelWarn+2, "between warn and error")
+	logger.Log(context.Background(), slog.LevelError+4, "beyond error")
+	for _, want := range []string{"warn", "error"} {
+		if entry := received(t, entries); entry.Level != want {
+			t.Fatalf("expected %s: %s", want, entry.Raw)
+		}
+	}
+}
+
+func TestFromSlog(t *testing.T) {
+	var out bytes.Buffer
+	logger := FromSlog(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	logger.Info().Msg("below the handler")
+	logger.Warn().Str("host", "a").Int("tries", 3).Msg("retrying")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("%v: %s", err, out.Bytes())
+	}
+	if record["level"] != "WARN" || record["msg"] != "retrying" || record["host"] != "a" || record["tries"] != float64(3) {
+		t.Fatalf("record: %s", out.Bytes())
+	}
+}
+
+func TestSlogLevelRoundTrip(t *testing.T) {
+	for level := TraceLevel; level < FatalLevel; level++ {
+		if _, name := levelOfSlog(slogLevel(level)); name != level.String() {
+			t.Fatalf("%s came back as %s", level, name)
+		}
+	}
+}
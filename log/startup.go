@@ -0,0 +1,63 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+)
+
+// NOTE set these before Init or at build time with -ldflags "-X github.com/osintami/sloan/log.VERSION=1.2.3",
+// empty values fall back to the executable name and the module version and vcs revision of the build
+var SERVICE string
+var VERSION string
+var COMMIT string
+
+// Startup logs the standard startup entry, InitLogger and Init call it once logging is set up
+func Startup() {
+	service, version, commit := buildInfo()
+	x := Info().Str("component", "osintami").
+		Str("service", service).
+		Str("version", version).
+		Str("commit", commit).
+		Str("go", runtime.Version()).
+		Str("os", runtime.GOOS).
+		Str("arch", runtime.GOARCH).
+		Int("pid", os.Getpid()).
+		Str("log_level", levelName(std.mask())).
+		Str("file", LOG_FILE).(*Event)
+	if data, err := DumpConfig(); err == nil {
+		sum := sha256.Sum256(data)
+		x.Str("config_hash", hex.EncodeToString(sum[:8]))
+		if std.current.Load() != nil {
+			x.Str("profile", active().config.Profile)
+			x.RawJSON("config", data)
+		}
+	}
+	if !x.ignore {
+		x.depth = 1
+		x.Msg("startup")
+	}
+}
+
+// buildInfo resolves SERVICE, VERSION and COMMIT with their fallbacks
+func buildInfo() (service, version, commit string) {
+	service, version, commit = SERVICE, VERSION, COMMIT
+	if service == "" {
+		service = filepath.Base(os.Args[0])
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if version == "" {
+			version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && commit == "" {
+				commit = setting.Value
+			}
+		}
+	}
+	return service, version, commit
+}
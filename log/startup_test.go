@@ -0,0 +1,28 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStartupMasksConfigSecrets(t *testing.T) {
+	entries, unsubscribe := Subscribe(func(x *Entry) bool { return x.Message == "startup" })
+	defer unsubscribe()
+	cfg := secretConfig()
+	cfg.Level = "info"
+	if err := Init(cfg); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case entry := <-entries:
+		if _, ok := entry.Fields["config"]; !ok {
+			t.Fatalf("startup entry has no config: %s", entry.Raw)
+		}
+		if strings.Contains(string(entry.Raw), "hunter2") {
+			t.Fatalf("secret in the startup entry: %s", entry.Raw)
+		}
+	default:
+		t.Fatal("no startup entry")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"os"
+	"sort"
+	"strconv"
+)
+
+// StandardFields returns the hostname, pid, service, version, commit and environment of the
+// process for Config.StaticFields, empty values are left out:
+//
+//	cfg.StaticFields = log.StandardFields("prod")
+func StandardFields(environment string) map[string]string {
+	service, version, commit := buildInfo()
+	fields := map[string]string{"pid": strconv.Itoa(os.Getpid()), "service": service}
+	if hostname, err := os.Hostname(); err == nil {
+		fields["hostname"] = hostname
+	}
+	for key, value := range map[string]string{"version": version, "commit": commit, "environment": environment} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// encodeStatic sorts the keys so every entry carries them in the same order
+func (x *state) encodeStatic(fields map[string]string) []byte {
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	buf := []byte{}
+	for _, key := range keys {
+		buf = appendString(appendKey(append(buf, ','), key), encryptValue(x, key, redactValue(x, key, fields[key])))
+	}
+	return buf
+}
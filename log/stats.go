@@ -0,0 +1,84 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// Stats summarizes log files: counts per level, per component and per time bucket
+type Stats struct {
+	Entries    int            `json:"entries"`
+	Malformed  int            `json:"malformed"`
+	First      time.Time      `json:"first"`
+	Last       time.Time      `json:"last"`
+	Bucket     string         `json:"bucket"`
+	Levels     map[string]int `json:"levels"`
+	Components map[string]int `json:"components"`
+	Buckets    map[string]int `json:"buckets"`
+	bucket     time.Duration
+}
+
+// NewStats counts entries in buckets of the given size, 0 disables time buckets
+func NewStats(bucket time.Duration) *Stats {
+	x := &Stats{Levels: map[string]int{}, Components: map[string]int{}, Buckets: map[string]int{}, bucket: bucket}
+	if bucket > 0 {
+		x.Bucket = bucket.String()
+	}
+	return x
+}
+
+func (x *Stats) Add(entry *Entry) {
+	x.Entries++
+	x.Levels[entry.Level]++
+	if component, ok := entry.Field("component"); ok {
+		x.Components[component]++
+	}
+	if entry.Time.IsZero() {
+		return
+	}
+	if x.First.IsZero() || entry.Time.Before(x.First) {
+		x.First = entry.Time
+	}
+	if entry.Time.After(x.Last) {
+		x.Last = entry.Time
+	}
+	if x.bucket > 0 {
+		x.Buckets[entry.Time.UTC().Truncate(x.bucket).Format(time.RFC3339)]++
+	}
+}
+
+func (x *Stats) Read(r io.Reader) error {
+	decoder := NewDecoder(r)
+	for {
+		entry, err := decoder.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if errors.Is(err, ErrMalformedEntry) {
+			x.Malformed++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		x.Add(entry)
+	}
+}
+
+func StatsFromFiles(bucket time.Duration, paths ...string) (*Stats, error) {
+	x := NewStats(bucket)
+	for _, path := range paths {
+		fh, err := OpenLog(path)
+		if err != nil {
+			return nil, err
+		}
+		err = x.Read(fh)
+		fh.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return x, nil
+}
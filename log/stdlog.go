@@ -0,0 +1,81 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"io"
+	stdlog "log"
+	"sync"
+)
+
+// NOTE a line longer than LINE_MAX is written in parts rather than held without bound
+const LINE_MAX = 64 * 1024
+
+// Writer returns an io.Writer of the default logger, see Logger.Writer
+func Writer(level Level) io.Writer {
+	return std.Writer(level)
+}
+
+// StdLogger returns a stdlib *log.Logger of the default logger, see Logger.StdLogger
+func StdLogger(level Level) *stdlog.Logger {
+	return std.StdLogger(level)
+}
+
+// Writer returns an io.Writer that writes each line written to it as the message of an entry
+// at level; partial lines wait for the rest, fatal and panic are written as error so captured
+// output never ends the process
+func (x *Logger) Writer(level Level) io.Writer {
+	if level > ErrorLevel {
+		level = ErrorLevel
+	}
+	bit, _ := levelOf(level.String())
+	return &lineWriter{logger: x, level: level, bit: bit}
+}
+
+// StdLogger returns a *log.Logger writing through Writer, e.g. for http.Server.ErrorLog
+func (x *Logger) StdLogger(level Level) *stdlog.Logger {
+	return stdlog.New(x.Writer(level), "", 0)
+}
+
+type lineWriter struct {
+	logger  *Logger
+	level   Level
+	bit     int // the LOG_* bit of level
+	mu      sync.Mutex
+	partial []byte
+}
+
+func (x *lineWriter) Write(p []byte) (int, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.partial = append(x.partial, p...)
+	for {
+		end := bytes.IndexByte(x.partial, '\n')
+		if end < 0 && len(x.partial) < LINE_MAX {
+			break
+		}
+		if end < 0 {
+			end = LINE_MAX
+		}
+		x.write(x.partial[:end])
+		x.partial = x.partial[min(end+1, len(x.partial)):]
+	}
+	if len(x.partial) == 0 {
+		x.partial = nil
+	}
+	return len(p), nil
+}
+
+func (x *lineWriter) write(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	e, ok := x.logger.leveled(x.bit, x.level.String()).(*Event)
+	if !ok || e.ignore {
+		return
+	}
+	// NOTE the caller would be this writer, not the code that wrote the line
+	e.caller = true
+	e.Msg(string(line))
+}
@@ -0,0 +1,34 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "time"
+
+// TimeTrack logs how long operation took at info level, use it as defer log.TimeTrack(time.Now(), "operation")
+func TimeTrack(start time.Time, operation string) {
+	x := Info().Str("operation", operation).Int64("duration_ms", time.Since(start).Milliseconds()).(*Event)
+	if !x.ignore {
+		x.depth = 1
+		x.Msg(operation + " done")
+	}
+}
+
+// Start records the time End measures from
+func (x *Event) Start() ILogger {
+	if x.ignore {
+		return x
+	}
+	x.start = time.Now()
+	return x
+}
+
+// End writes the event with duration_ms since Start
+func (x *Event) End(msg string) {
+	if x.ignore {
+		return
+	}
+	if !x.start.IsZero() {
+		x.Int64("duration_ms", time.Since(x.start).Milliseconds())
+	}
+	x.depth++
+	x.Msg(msg)
+}
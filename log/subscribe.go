@@ -0,0 +1,82 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// SUBSCRIBER_BUFFER is how many entries a slow subscriber may fall behind before entries are dropped for it
+const SUBSCRIBER_BUFFER = 256
+
+// Filter selects the entries a subscriber receives, nil receives everything
+type Filter func(*Entry) bool
+
+type subscriber struct {
+	filter  Filter
+	ch      chan Entry
+	dropped atomic.Uint64
+}
+
+var subscribersMu sync.Mutex
+var subscribers atomic.Pointer[[]*subscriber]
+
+// Subscribe delivers every written entry that passes filter, logging never blocks on a
+// subscriber so a full channel drops entries; call the returned func to unsubscribe
+func Subscribe(filter Filter) (<-chan Entry, func()) {
+	x := &subscriber{filter: filter, ch: make(chan Entry, SUBSCRIBER_BUFFER)}
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	list := append(currentSubscribers(), x)
+	subscribers.Store(&list)
+
+	var once sync.Once
+	return x.ch, func() {
+		once.Do(func() {
+			subscribersMu.Lock()
+			defer subscribersMu.Unlock()
+			list := []*subscriber{}
+			for _, item := range currentSubscribers() {
+				if item != x {
+					list = append(list, item)
+				}
+			}
+			subscribers.Store(&list)
+			close(x.ch)
+		})
+	}
+}
+
+func currentSubscribers() []*subscriber {
+	if list := subscribers.Load(); list != nil {
+		return append([]*subscriber{}, (*list)...)
+	}
+	return nil
+}
+
+// publish decodes the line only when someone is listening
+func publish(out []byte) {
+	list := subscribers.Load()
+	if list == nil || len(*list) == 0 {
+		return
+	}
+	entry, err := ParseEntry(out[:len(out)-1])
+	if err != nil {
+		diagnose("publish", "", err)
+		return
+	}
+	// NOTE hold the lock so an unsubscribe cannot close a channel mid send
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	for _, x := range *subscribers.Load() {
+		if x.filter != nil && !x.filter(entry) {
+			continue
+		}
+		select {
+		case x.ch <- *entry:
+		default:
+			x.dropped.Add(1)
+			metricDropped.Add(1)
+		}
+	}
+}
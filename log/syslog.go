@@ -0,0 +1,157 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyslogConfig applies to sinks of type "syslog", whose Path is empty for the local syslog
+// daemon or an address like "udp://siem:514", "tcp://siem:601" or "unix:///dev/log"; Facility
+// is kern to local7 and "user" when empty, Format rfc5424 (the default) or rfc3164 and Tag the
+// app name, SERVICE when empty
+type SyslogConfig struct {
+	Facility string `json:"facility" yaml:"facility" toml:"facility"`
+	Format   string `json:"format" yaml:"format" toml:"format"`
+	Tag      string `json:"tag" yaml:"tag" toml:"tag"`
+}
+
+var SYSLOG_FACILITIES = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// NOTE trace and debug are debug(7), info is info(6), warn is warning(4), error is err(3) and
+// fatal, panic and audit entries are crit(2)
+var SYSLOG_SEVERITIES = map[Level]int{
+	TraceLevel: 7, DebugLevel: 7, InfoLevel: 6, WarnLevel: 4, ErrorLevel: 3, FatalLevel: 2,
+}
+
+// SyslogWriter sends every entry as one syslog message with the JSON line as its text through a
+// NetWriter, so a syslog server that is down or not up yet never blocks a log call: messages
+// wait in its buffer while it reconnects with backoff
+type SyslogWriter struct {
+	network  string
+	address  string
+	priority int
+	rfc3164  bool
+	tag      string
+	hostname string
+	net      *NetWriter
+}
+
+func NewSyslogWriter(address string, cfg SyslogConfig) (*SyslogWriter, error) {
+	facility := "user"
+	if cfg.Facility != "" {
+		facility = strings.ToLower(cfg.Facility)
+	}
+	priority, ok := SYSLOG_FACILITIES[facility]
+	if !ok {
+		return nil, fmt.Errorf("syslog: unknown facility %q", cfg.Facility)
+	}
+	x := &SyslogWriter{priority: priority * 8, rfc3164: strings.EqualFold(cfg.Format, "rfc3164"), tag: cfg.Tag}
+	if x.tag == "" {
+		x.tag, _, _ = buildInfo()
+	}
+	if address != "" {
+		target, err := url.Parse(address)
+		if err != nil || (target.Scheme != "udp" && target.Scheme != "tcp" && target.Scheme != "unix") {
+			return nil, fmt.Errorf("syslog: address %q is not udp://, tcp:// or unix://", address)
+		}
+		x.network, x.address = target.Scheme, target.Host
+		if target.Scheme == "unix" {
+			x.address = target.Path
+		} else {
+			x.hostname, _ = os.Hostname()
+		}
+	}
+	if x.network == "" || x.network == "unix" {
+		x.network, x.address = localSyslog(x.address)
+	}
+	x.net = NewNetWriter(x.network, x.address)
+	return x, nil
+}
+
+// localSyslog picks the socket of the local daemon, datagram first, trying the usual paths when
+// none is given; a local connect never waits, and when nothing listens yet the first path is
+// used and the NetWriter keeps trying
+func localSyslog(address string) (string, string) {
+	paths := []string{address}
+	if address == "" {
+		paths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+	}
+	for _, path := range paths {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				conn.Close()
+				return network, path
+			}
+		}
+	}
+	return "unixgram", paths[0]
+}
+
+func (x *SyslogWriter) Write(p []byte) (int, error) {
+	return x.WriteLevel(InfoLevel, p)
+}
+
+// WriteLevel queues the message, see NetWriter.Write
+func (x *SyslogWriter) WriteLevel(level Level, p []byte) (int, error) {
+	if _, err := x.net.Write(x.format(level, p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Backlog is the number of messages waiting to be sent
+func (x *SyslogWriter) Backlog() int {
+	return x.net.Backlog()
+}
+
+// Flush sends the queued messages before ctx is done, see Logger.Flush
+func (x *SyslogWriter) Flush(ctx context.Context) error {
+	return x.net.Flush(ctx)
+}
+
+// format frames stream connections, tcp and the unix stream socket, by octet counting for
+// rfc5424 and by newline for rfc3164
+func (x *SyslogWriter) format(level Level, p []byte) []byte {
+	severity, ok := SYSLOG_SEVERITIES[level]
+	if !ok {
+		severity = 6
+	}
+	text := strings.TrimRight(string(p), "\n")
+	msg := ""
+	if x.rfc3164 {
+		host := ""
+		if x.hostname != "" {
+			host = x.hostname + " "
+		}
+		msg = fmt.Sprintf("<%d>%s %s%s[%d]: %s", x.priority+severity, time.Now().Format(time.Stamp), host, x.tag, os.Getpid(), text)
+	} else {
+		host := x.hostname
+		if host == "" {
+			host = "-"
+		}
+		msg = fmt.Sprintf("<%d>1 %s %s %s %d - - %s", x.priority+severity, time.Now().Format("2006-01-02T15:04:05.000000Z07:00"), host, x.tag, os.Getpid(), text)
+	}
+	if x.network == "tcp" || x.network == "unix" {
+		if x.rfc3164 {
+			return []byte(msg + "\n")
+		}
+		return []byte(strconv.Itoa(len(msg)) + " " + msg)
+	}
+	return []byte(msg)
+}
+
+// Close sends the queued messages, waiting up to NET_CLOSE_TIMEOUT
+func (x *SyslogWriter) Close() error {
+	return x.net.Close()
+}
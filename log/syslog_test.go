@@ -0,0 +1,105 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const syslogLine = `{"level":"error","message":"disk full"}` + "\n"
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	w, err := NewSyslogWriter("udp://"+conn.LocalAddr().String(), SyslogConfig{Facility: "local0", Tag: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if _, err := w.WriteLevel(ErrorLevel, []byte(syslogLine)); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	buffer := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NOTE local0 is 16, err is 3
+	msg := string(buffer[:n])
+	if !strings.HasPrefix(msg, "<131>1 ") || !strings.Contains(msg, " app ") || !strings.HasSuffix(msg, " - - "+strings.TrimSpace(syslogLine)) {
+		t.Fatalf("rfc5424 message: %q", msg)
+	}
+}
+
+func TestSyslogTCPFraming(t *testing.T) {
+	cases := map[string]func(t *testing.T, r *bufio.Reader){
+		"rfc5424": func(t *testing.T, r *bufio.Reader) {
+			size, err := r.ReadString(' ')
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(size))
+			if err != nil {
+				t.Fatalf("octet count: %q", size)
+			}
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil || !strings.HasPrefix(string(msg), "<14>1") || !strings.HasSuffix(string(msg), strings.TrimSpace(syslogLine)) {
+				t.Fatalf("octet counted message: %q %v", msg, err)
+			}
+		},
+		"rfc3164": func(t *testing.T, r *bufio.Reader) {
+			msg, err := r.ReadString('\n')
+			if err != nil || !strings.HasPrefix(msg, "<14>") || !strings.Contains(msg, " app[") || !strings.HasSuffix(msg, syslogLine) {
+				t.Fatalf("newline framed message: %q %v", msg, err)
+			}
+		},
+	}
+	for format, check := range cases {
+		t.Run(format, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+			w, err := NewSyslogWriter("tcp://"+listener.Addr().String(), SyslogConfig{Format: format, Tag: "app"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+			if _, err := w.Write([]byte(syslogLine)); err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if err := w.Flush(ctx); err != nil {
+				t.Fatal(err)
+			}
+			conn, err := listener.Accept()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			check(t, bufio.NewReader(conn))
+		})
+	}
+}
+
+func TestSyslogRefusesBadConfig(t *testing.T) {
+	if _, err := NewSyslogWriter("", SyslogConfig{Facility: "nosuch"}); err == nil {
+		t.Fatal("accepted an unknown facility")
+	}
+	if _, err := NewSyslogWriter("http://siem:514", SyslogConfig{}); err == nil {
+		t.Fatal("accepted an http address")
+	}
+}
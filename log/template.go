@@ -0,0 +1,44 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var placeholder = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// render replaces {key} in msg with the value of field key as written, so a redacted or
+// encrypted field stays that way in the message; unknown keys are left as they are
+func (x *Event) render(msg string) string {
+	if !strings.Contains(msg, "{") {
+		return msg
+	}
+	return placeholder.ReplaceAllStringFunc(msg, func(match string) string {
+		value := fieldValue(x.buf, match[1:len(match)-1])
+		if value == nil {
+			return match
+		}
+		text := ""
+		if json.Unmarshal(value, &text) != nil {
+			return string(value)
+		}
+		return text
+	})
+}
+
+// fieldValue is the JSON value of the last field key in buf, nil when there is none
+func fieldValue(buf []byte, key string) json.RawMessage {
+	prefix := appendKey([]byte{','}, key)
+	i := bytes.LastIndex(buf, prefix)
+	if i < 0 {
+		return nil
+	}
+	var value json.RawMessage
+	if json.NewDecoder(bytes.NewReader(buf[i+len(prefix):])).Decode(&value) != nil {
+		return nil
+	}
+	return value
+}
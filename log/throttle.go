@@ -0,0 +1,70 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+type throttle struct {
+	key        string
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+	timer      *time.Timer
+}
+
+// NOTE keys are never forgotten, use a fixed set such as the name of the retry loop
+var throttles sync.Map
+
+// Throttle returns a warn logger for the first call per key in every interval and a no-op logger
+// otherwise; the calls dropped in an interval are counted as "suppressed" in an entry of their
+// own when it ends, or when the default logger is flushed or closed before that
+func Throttle(key string, interval time.Duration) ILogger {
+	value, _ := throttles.LoadOrStore(key, &throttle{key: key})
+	x := value.(*throttle)
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	now := time.Now()
+	if !x.last.IsZero() && now.Sub(x.last) < interval {
+		x.suppressed++
+		if x.timer == nil {
+			x.timer = time.AfterFunc(x.last.Add(interval).Sub(now), x.flush)
+		}
+		return disabled
+	}
+	x.last = now
+	logger := Warn()
+	// NOTE the interval ended before its timer got to write the count
+	if x.suppressed > 0 {
+		logger.Int("suppressed", x.suppressed)
+		x.suppressed = 0
+	}
+	if x.timer != nil {
+		x.timer.Stop()
+		x.timer = nil
+	}
+	return logger
+}
+
+// flush writes the count of the calls dropped since the last entry of the key
+func (x *throttle) flush() {
+	x.mu.Lock()
+	count := x.suppressed
+	x.suppressed = 0
+	if x.timer != nil {
+		x.timer.Stop()
+		x.timer = nil
+	}
+	x.mu.Unlock()
+	if count > 0 {
+		Warn().Str("throttle", x.key).Int("suppressed", count).Msg("throttled")
+	}
+}
+
+func flushThrottles() {
+	throttles.Range(func(_, value interface{}) bool {
+		value.(*throttle).flush()
+		return true
+	})
+}
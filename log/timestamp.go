@@ -0,0 +1,81 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// appendTimestamp writes t as Config.Timestamp says, the unix forms as numbers
+func appendTimestamp(dst []byte, cfg Config, t time.Time) []byte {
+	if cfg.UTC {
+		t = t.UTC()
+	}
+	layout := cfg.Timestamp
+	switch strings.ToLower(cfg.Timestamp) {
+	case "", "rfc3339":
+		layout = time.RFC3339
+	case "rfc3339nano":
+		layout = time.RFC3339Nano
+	case "unix":
+		return strconv.AppendInt(dst, t.Unix(), 10)
+	case "unixms":
+		return strconv.AppendInt(dst, t.UnixMilli(), 10)
+	case "unixnano":
+		return strconv.AppendInt(dst, t.UnixNano(), 10)
+	}
+	return append(t.AppendFormat(append(dst, '"'), layout), '"')
+}
+
+// parseTimestamp reads a time written by appendTimestamp, RFC 3339 is always understood
+func parseTimestamp(value interface{}, cfg Config) time.Time {
+	switch value := value.(type) {
+	case json.Number:
+		n, err := value.Int64()
+		if err != nil {
+			return time.Time{}
+		}
+		switch strings.ToLower(cfg.Timestamp) {
+		case "unixms":
+			return time.UnixMilli(n)
+		case "unixnano":
+			return time.Unix(0, n)
+		}
+		return time.Unix(n, 0)
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+		t, _ := time.Parse(cfg.Timestamp, value)
+		return t
+	}
+	return time.Time{}
+}
+
+func (x Config) timeKey() string {
+	if x.TimeKey == "" {
+		return "time"
+	}
+	return x.TimeKey
+}
+
+func (x Config) levelKey() string {
+	if x.LevelKey == "" {
+		return "level"
+	}
+	return x.LevelKey
+}
+
+func (x Config) messageKey() string {
+	if x.MessageKey == "" {
+		return "message"
+	}
+	return x.MessageKey
+}
+
+// appendLevel writes the level under Config.LevelKey
+func (x *Event) appendLevel(name string) {
+	x.buf = appendString(appendKey(append(x.buf, ','), x.st.config.levelKey()), name)
+}
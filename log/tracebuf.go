@@ -0,0 +1,91 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import "sync"
+
+// TraceBuffer keeps the last debug entries of one request or goroutine in memory and writes
+// them just before an error or fatal entry of the same buffer, so failures come with context
+// without paying for debug output the rest of the time; debug entries are written right away
+// when the level allows them anyway
+type TraceBuffer struct {
+	mu    sync.Mutex
+	lines [][]byte
+	next  int
+	full  bool
+}
+
+// NewTraceBuffer keeps up to size debug and trace entries, create one per request
+func NewTraceBuffer(size int) *TraceBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &TraceBuffer{lines: make([][]byte, size)}
+}
+
+// Debug skips the LOG_LEVEL check, Msg decides between writing and buffering
+func (x *TraceBuffer) Debug() ILogger {
+	return x.buffered(LOG_DEBUG, "debug")
+}
+
+func (x *TraceBuffer) Trace() ILogger {
+	return x.buffered(LOG_TRACE, "trace")
+}
+
+func (x *TraceBuffer) buffered(level int, name string) ILogger {
+	e := newEvent(std, std.active(), level)
+	e.appendLevel(name)
+	e.buf = append(e.buf, e.st.static...)
+	e.trace = x
+	return e
+}
+
+func (x *TraceBuffer) Info() ILogger {
+	return x.wrap(Info())
+}
+
+func (x *TraceBuffer) Warn() ILogger {
+	return x.wrap(Warn())
+}
+
+func (x *TraceBuffer) Error() ILogger {
+	return x.wrap(Error())
+}
+
+func (x *TraceBuffer) Fatal() ILogger {
+	return x.wrap(Fatal())
+}
+
+func (x *TraceBuffer) Panic() ILogger {
+	return x.wrap(Panic())
+}
+
+func (x *TraceBuffer) wrap(logger ILogger) ILogger {
+	if e := logger.(*Event); !e.ignore {
+		e.trace = x
+	}
+	return logger
+}
+
+func (x *TraceBuffer) push(line []byte) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.lines[x.next] = line
+	x.next = (x.next + 1) % len(x.lines)
+	x.full = x.full || x.next == 0
+}
+
+// drain returns the buffered lines oldest first and empties the buffer
+func (x *TraceBuffer) drain() [][]byte {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	lines := [][]byte{}
+	if x.full {
+		lines = append(lines, x.lines[x.next:]...)
+	}
+	lines = append(lines, x.lines[:x.next]...)
+	for i := range x.lines {
+		x.lines[i] = nil
+	}
+	x.next, x.full = 0, false
+	return lines
+}
@@ -0,0 +1,387 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Validate reports every contradiction in the config at once so it can be fixed before the logger starts
+func (x Config) Validate() error {
+	problems := []error{}
+	if _, ok := PROFILES[x.Profile]; x.Profile != "" && !ok {
+		problems = append(problems, fmt.Errorf("profile: unknown profile %q, use dev, prod, test or quiet", x.Profile))
+	}
+	if x.Format != "" && x.Format != "json" && x.Format != "console" {
+		problems = append(problems, fmt.Errorf("format: unknown format %q, use json or console", x.Format))
+	}
+	if x.Level != "" {
+		if _, ok := parseLevel(x.Level); !ok {
+			problems = append(problems, fmt.Errorf("level: unknown log level %q, use trace, debug, info, warn, error or fatal", x.Level))
+		}
+	}
+
+	if x.CallerSkip < 0 {
+		problems = append(problems, fmt.Errorf("caller_skip: must not be negative"))
+	}
+	if x.ErrorStackDepth < 0 {
+		problems = append(problems, fmt.Errorf("error_stack_depth: must not be negative"))
+	}
+	if _, ok := durationUnits[x.DurationUnit]; x.DurationUnit != "" && !ok {
+		problems = append(problems, fmt.Errorf("duration_unit: unknown unit %q, use ns, us, ms or s", x.DurationUnit))
+	}
+
+	problems = append(problems, validateSinks("sinks", x.Sinks)...)
+
+	problems = append(problems, x.validateRotation()...)
+
+	for _, pattern := range x.Redact.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, fmt.Errorf("redact: pattern %q does not compile: %w", pattern, err))
+		}
+	}
+
+	problems = append(problems, validateSampling("sampling", x.Sampling)...)
+	if x.Dedup.Window != "" {
+		if window, err := time.ParseDuration(x.Dedup.Window); err != nil || window <= 0 {
+			problems = append(problems, fmt.Errorf("dedup.window: %q is not a positive duration", x.Dedup.Window))
+		}
+	} else if len(x.Dedup.Fields) > 0 {
+		problems = append(problems, fmt.Errorf("dedup.fields: only apply with a window"))
+	}
+	if x.Async.Buffer < 0 {
+		problems = append(problems, fmt.Errorf("async.buffer: must not be negative"))
+	}
+	switch x.Async.Policy {
+	case "", "block", "drop":
+	default:
+		problems = append(problems, fmt.Errorf("async.policy: unknown policy %q, use block or drop", x.Async.Policy))
+	}
+	if (x.Audit.Chain || x.Audit.SigningKey != "" || x.Audit.WORM || x.Audit.HMAC != "") && x.Audit.Path == "" {
+		problems = append(problems, fmt.Errorf("audit: chain, signing_key, worm and hmac require an audit path"))
+	}
+	if x.Audit.HMAC != "" {
+		if _, err := lookupKey(x.Audit.HMAC); err != nil {
+			problems = append(problems, fmt.Errorf("audit: %w", err))
+		}
+	}
+	problems = append(problems, x.validateRetention()...)
+	for field, keyID := range x.EncryptFields {
+		for _, key := range x.Redact.Keys {
+			if strings.EqualFold(key, field) {
+				problems = append(problems, fmt.Errorf("encrypt_fields.%s: field is also redacted, drop it from one of them", field))
+			}
+		}
+		if _, err := lookupKey(keyID); err != nil {
+			problems = append(problems, fmt.Errorf("encrypt_fields.%s: %w", field, err))
+		}
+	}
+	if x.Audit.Encrypt != "" {
+		if _, err := lookupKey(x.Audit.Encrypt); err != nil {
+			problems = append(problems, fmt.Errorf("audit: %w", err))
+		}
+	}
+	if x.Audit.SigningKey != "" {
+		if _, err := LoadSigningKey(x.Audit.SigningKey); err != nil {
+			problems = append(problems, fmt.Errorf("audit: %w", err))
+		}
+	}
+
+	if strings.ContainsAny(x.Timestamp, "\"\\") {
+		problems = append(problems, fmt.Errorf("timestamp: layout %q must not contain quotes or backslashes", x.Timestamp))
+	}
+	reserved := map[string]bool{"": true, "seq": true, "caller": true, "function": true}
+	for _, key := range []string{x.timeKey(), x.levelKey(), x.messageKey()} {
+		if reserved[key] {
+			problems = append(problems, fmt.Errorf("time_key, level_key and message_key must be distinct and not seq, caller or function, got %q", key))
+		}
+		reserved[key] = true
+	}
+	for key := range x.StaticFields {
+		if reserved[key] {
+			problems = append(problems, fmt.Errorf("static_fields: key %q is reserved", key))
+		}
+	}
+	for name, level := range x.Levels {
+		if _, ok := parseLevel(level); !ok {
+			problems = append(problems, fmt.Errorf("levels.%s: unknown log level %q", name, level))
+		} else if item, ok := x.Components[name]; ok && item.Level != "" && !strings.EqualFold(item.Level, level) {
+			problems = append(problems, fmt.Errorf("levels.%s: %q contradicts components.%s.level %q", name, level, name, item.Level))
+		}
+	}
+	for name, item := range x.Components {
+		prefix := fmt.Sprintf("components.%s", name)
+		if item.Level != "" {
+			if _, ok := parseLevel(item.Level); !ok {
+				problems = append(problems, fmt.Errorf("%s.level: unknown log level %q", prefix, item.Level))
+			}
+		}
+		problems = append(problems, validateSinks(prefix+".sinks", item.Sinks)...)
+		if item.Sampling != nil {
+			problems = append(problems, validateSampling(prefix+".sampling", *item.Sampling)...)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func validateSinks(prefix string, sinks []SinkConfig) []error {
+	problems := []error{}
+	for i, item := range sinks {
+		switch item.Type {
+		case "stderr", "stdout":
+			if item.Path != "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: %s sink does not take a path, use type \"file\"", prefix, i, item.Type))
+			}
+		case "file":
+			if item.Path == "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: file sink requires a path", prefix, i))
+			}
+		case "journald":
+			if item.Path != "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: journald sink does not take a path", prefix, i))
+			}
+		case "net":
+			target, err := url.Parse(item.Path)
+			if err != nil || item.Path == "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: net sink requires a URL, e.g. tcp://collector:5170", prefix, i))
+				break
+			}
+			switch target.Scheme {
+			case "tcp", "tls", "udp":
+				if target.Port() == "" {
+					problems = append(problems, fmt.Errorf("%s[%d]: net sink %q has no port", prefix, i, item.Path))
+				}
+			case "unix", "unixgram":
+				if target.Path == "" {
+					problems = append(problems, fmt.Errorf("%s[%d]: net sink %q has no socket path", prefix, i, item.Path))
+				}
+			default:
+				problems = append(problems, fmt.Errorf("%s[%d]: unknown net scheme %q, use tcp, tls, udp, unix or unixgram", prefix, i, target.Scheme))
+			}
+		case "loki":
+			if target, err := url.Parse(item.Path); err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: loki sink requires an http:// or https:// URL, e.g. http://loki:3100", prefix, i))
+			}
+			for key := range item.Loki.Labels {
+				if !lokiLabel.MatchString(key) || key == "level" {
+					problems = append(problems, fmt.Errorf("%s[%d]: invalid loki label %q, names are [a-zA-Z_][a-zA-Z0-9_]* and level is set per entry", prefix, i, key))
+				}
+			}
+			if item.Loki.BatchWait != "" {
+				if wait, err := time.ParseDuration(item.Loki.BatchWait); err != nil || wait <= 0 {
+					problems = append(problems, fmt.Errorf("%s[%d]: invalid loki batch_wait %q", prefix, i, item.Loki.BatchWait))
+				}
+			}
+			if item.Loki.BatchSize < 0 {
+				problems = append(problems, fmt.Errorf("%s[%d]: loki batch_size must not be negative", prefix, i))
+			}
+		case "elastic":
+			if target, err := url.Parse(item.Path); err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: elastic sink requires an http:// or https:// URL, e.g. http://es:9200", prefix, i))
+			}
+			if !validIndex(item.Elastic.Index) {
+				problems = append(problems, fmt.Errorf("%s[%d]: elastic index %q needs a closing } after its {", prefix, i, item.Elastic.Index))
+			}
+			if item.Elastic.BatchWait != "" {
+				if wait, err := time.ParseDuration(item.Elastic.BatchWait); err != nil || wait <= 0 {
+					problems = append(problems, fmt.Errorf("%s[%d]: invalid elastic batch_wait %q", prefix, i, item.Elastic.BatchWait))
+				}
+			}
+			if item.Elastic.BatchSize < 0 {
+				problems = append(problems, fmt.Errorf("%s[%d]: elastic batch_size must not be negative", prefix, i))
+			}
+		case "webhook":
+			if target, err := url.Parse(item.Path); err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: webhook sink requires an http:// or https:// URL", prefix, i))
+			}
+			if _, err := template.New("webhook").Funcs(webhookFuncs).Parse(item.Webhook.Template); err != nil {
+				problems = append(problems, fmt.Errorf("%s[%d]: webhook template: %w", prefix, i, err))
+			}
+			if item.Webhook.Period != "" {
+				if period, err := time.ParseDuration(item.Webhook.Period); err != nil || period <= 0 {
+					problems = append(problems, fmt.Errorf("%s[%d]: invalid webhook period %q", prefix, i, item.Webhook.Period))
+				}
+			}
+			if item.Webhook.Limit < 0 {
+				problems = append(problems, fmt.Errorf("%s[%d]: webhook limit must not be negative", prefix, i))
+			}
+		case "otlp":
+			protocol := strings.ToLower(item.OTLP.Protocol)
+			if _, ok := otlpProtocols[protocol]; !ok && protocol != "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: unknown otlp protocol %q, use %s", prefix, i, item.OTLP.Protocol, otlpProtocolNames()))
+			}
+			if target, err := url.Parse(item.Path); (protocol == "" || protocol == "http/json") && (err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "") {
+				problems = append(problems, fmt.Errorf("%s[%d]: otlp sink requires an http:// or https:// endpoint, e.g. http://otel-collector:4318", prefix, i))
+			} else if item.Path == "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: otlp sink requires an endpoint", prefix, i))
+			}
+			if item.OTLP.BatchWait != "" {
+				if wait, err := time.ParseDuration(item.OTLP.BatchWait); err != nil || wait <= 0 {
+					problems = append(problems, fmt.Errorf("%s[%d]: invalid otlp batch_wait %q", prefix, i, item.OTLP.BatchWait))
+				}
+			}
+			if item.OTLP.BatchSize < 0 {
+				problems = append(problems, fmt.Errorf("%s[%d]: otlp batch_size must not be negative", prefix, i))
+			}
+		case "syslog":
+			if _, ok := SYSLOG_FACILITIES[strings.ToLower(item.Syslog.Facility)]; !ok && item.Syslog.Facility != "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: unknown syslog facility %q", prefix, i, item.Syslog.Facility))
+			}
+			switch strings.ToLower(item.Syslog.Format) {
+			case "", "rfc5424", "rfc3164":
+			default:
+				problems = append(problems, fmt.Errorf("%s[%d]: unknown syslog format %q, use rfc5424 or rfc3164", prefix, i, item.Syslog.Format))
+			}
+		default:
+			problems = append(problems, fmt.Errorf("%s[%d]: unknown sink type %q, use stderr, stdout, file, syslog, journald, net, loki, elastic, webhook or otlp", prefix, i, item.Type))
+		}
+		if item.Level != "" {
+			if _, ok := parseLevel(item.Level); !ok {
+				problems = append(problems, fmt.Errorf("%s[%d]: unknown log level %q", prefix, i, item.Level))
+			}
+		}
+		if item.Index && item.Type != "file" {
+			problems = append(problems, fmt.Errorf("%s[%d]: index is only supported on file sinks", prefix, i))
+		}
+		if (item.Chain || item.SigningKey != "") && item.Type != "file" {
+			problems = append(problems, fmt.Errorf("%s[%d]: chain and signing_key are only supported on file sinks", prefix, i))
+		}
+		if item.SigningKey != "" {
+			if _, err := LoadSigningKey(item.SigningKey); err != nil {
+				problems = append(problems, fmt.Errorf("%s[%d]: %w", prefix, i, err))
+			}
+		}
+		if _, ok := encoders[strings.ToLower(item.Format)]; !ok && item.Format != "" {
+			problems = append(problems, fmt.Errorf("%s[%d]: unknown format %q, use %s", prefix, i, item.Format, encoderNames()))
+		} else if item.Format != "" && !strings.EqualFold(item.Format, "json") {
+			if item.Pretty {
+				problems = append(problems, fmt.Errorf("%s[%d]: pretty only applies to the json format", prefix, i))
+			}
+			if item.Index || item.Chain || item.SigningKey != "" || item.Encrypt != "" || item.HMAC != "" {
+				problems = append(problems, fmt.Errorf("%s[%d]: index, chain, signing_key, encrypt and hmac need the json format", prefix, i))
+			}
+			if item.Type == "elastic" || item.Type == "webhook" || item.Type == "otlp" {
+				problems = append(problems, fmt.Errorf("%s[%d]: %s sinks read json entries, use the json format", prefix, i, item.Type))
+			}
+		}
+		if item.Pretty && item.Type != "stderr" && item.Type != "stdout" {
+			problems = append(problems, fmt.Errorf("%s[%d]: pretty is only supported on stderr and stdout, files are one entry per line", prefix, i))
+		}
+		if item.WORM && item.Type != "file" {
+			problems = append(problems, fmt.Errorf("%s[%d]: worm is only supported on file sinks", prefix, i))
+		}
+		if item.HMAC != "" {
+			if item.Type != "file" {
+				problems = append(problems, fmt.Errorf("%s[%d]: hmac is only supported on file sinks", prefix, i))
+			}
+			if _, err := lookupKey(item.HMAC); err != nil {
+				problems = append(problems, fmt.Errorf("%s[%d]: %w", prefix, i, err))
+			}
+		}
+		if item.Encrypt != "" {
+			if item.Type != "file" {
+				problems = append(problems, fmt.Errorf("%s[%d]: encrypt is only supported on file sinks", prefix, i))
+			}
+			if item.Index {
+				problems = append(problems, fmt.Errorf("%s[%d]: index cannot be used with encrypt", prefix, i))
+			}
+			if _, err := lookupKey(item.Encrypt); err != nil {
+				problems = append(problems, fmt.Errorf("%s[%d]: %w", prefix, i, err))
+			}
+		}
+	}
+	return problems
+}
+
+func validateSampling(prefix string, sampling SamplingConfig) []error {
+	problems := []error{}
+	if sampling.Every < 0 || sampling.Burst < 0 {
+		problems = append(problems, fmt.Errorf("%s: every and burst must not be negative", prefix))
+	}
+	if sampling.Period != "" {
+		if period, err := time.ParseDuration(sampling.Period); err != nil || period <= 0 {
+			problems = append(problems, fmt.Errorf("%s: period %q is not a positive duration", prefix, sampling.Period))
+		} else if sampling.Burst == 0 {
+			problems = append(problems, fmt.Errorf("%s: period only applies with a burst", prefix))
+		}
+	}
+	for _, name := range sampling.Levels {
+		level, ok := levelOf(name)
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s: unknown log level %q", prefix, name))
+		} else if level == LOG_FATAL {
+			problems = append(problems, fmt.Errorf("%s: fatal events must never be sampled", prefix))
+		}
+	}
+	return problems
+}
+
+func (x Config) validateRotation() []error {
+	problems := []error{}
+	if x.Rotation.MaxSizeMB < 0 || x.Rotation.MaxBackups < 0 || x.Rotation.MaxAgeDays < 0 {
+		problems = append(problems, fmt.Errorf("rotation: max_size_mb, max_backups and max_age_days must not be negative"))
+	}
+	if x.Rotation.Interval != "" {
+		if every, err := time.ParseDuration(x.Rotation.Interval); err != nil || every < time.Minute {
+			problems = append(problems, fmt.Errorf("rotation.interval: %q is not a duration of at least 1m, e.g. \"24h\"", x.Rotation.Interval))
+		}
+	}
+	if (x.Rotation.MaxBackups > 0 || x.Rotation.MaxAgeDays > 0 || x.Rotation.Compress) && x.Rotation.MaxSizeMB == 0 && x.Rotation.Interval == "" {
+		problems = append(problems, fmt.Errorf("rotation: max_size_mb is 0 and no interval is set, files will never rotate"))
+	}
+	sinks := append([]SinkConfig{}, x.Sinks...)
+	for _, item := range x.Components {
+		sinks = append(sinks, item.Sinks...)
+	}
+	if x.Audit.Path != "" {
+		sinks = append(sinks, SinkConfig{Type: "file", Path: x.Audit.Path, WORM: x.Audit.WORM, HMAC: x.Audit.HMAC})
+	}
+	for _, item := range sinks {
+		if item.WORM && (x.Rotation.MaxAgeDays > 0 || x.Rotation.Compress) {
+			problems = append(problems, fmt.Errorf("rotation: %s is a worm sink, its backups are never removed or compressed", item.Path))
+		}
+		if x.Rotation.Compress && (item.HMAC != "" || item.Index) {
+			problems = append(problems, fmt.Errorf("rotation: %s has an hmac or index file, which cannot follow a compressed backup", item.Path))
+		}
+	}
+	if x.Rotation.Compress && x.Retention.Action == "anonymize" {
+		problems = append(problems, fmt.Errorf("rotation: compressed backups cannot be anonymized in place by retention"))
+	}
+	return problems
+}
+
+func (x Config) validateRetention() []error {
+	problems := []error{}
+	if x.Retention.Days < 0 {
+		problems = append(problems, fmt.Errorf("retention.days: must not be negative"))
+	}
+	switch x.Retention.Action {
+	case "", "delete", "anonymize":
+	default:
+		problems = append(problems, fmt.Errorf("retention.action: unknown action %q, use delete or anonymize", x.Retention.Action))
+	}
+	if x.Retention.Days <= 0 {
+		return problems
+	}
+	sinks := append([]SinkConfig{}, x.Sinks...)
+	for _, item := range x.Components {
+		sinks = append(sinks, item.Sinks...)
+	}
+	for _, item := range sinks {
+		if item.WORM {
+			problems = append(problems, fmt.Errorf("retention: %s is a worm sink and cannot be purged", item.Path))
+		}
+		if x.Retention.Action == "anonymize" && (item.Chain || item.SigningKey != "" || item.Encrypt != "") {
+			problems = append(problems, fmt.Errorf("retention: %s is chained or encrypted and cannot be anonymized in place", item.Path))
+		}
+		if x.Retention.Action == "anonymize" && item.Type == "file" && item.Format != "" && !strings.EqualFold(item.Format, "json") {
+			problems = append(problems, fmt.Errorf("retention: %s is not json and cannot be anonymized in place", item.Path))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,214 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// NOTE a WebhookWriter posts one request per entry, it holds up to WEBHOOK_BUFFER while posting
+// and sends at most WEBHOOK_LIMIT per WEBHOOK_PERIOD unless configured otherwise
+const (
+	WEBHOOK_BUFFER  = 256
+	WEBHOOK_LIMIT   = 30
+	WEBHOOK_PERIOD  = time.Minute
+	WEBHOOK_TIMEOUT = 10 * time.Second
+)
+
+// WebhookConfig applies to sinks of type "webhook", whose Path is the URL posted to; Template
+// is a text/template of the body run on the parsed Entry, with json to quote a value, e.g.
+// `{"text": {{json .Message}}}` for Slack, the JSON line when empty; Match keeps the entries
+// whose fields have all the given values, the sink level filters by level; Auth is sent as the
+// Authorization header with ${VAR} expanded from the environment so the secret stays out of
+// the config; Limit posts per Period at most, the rest are dropped
+type WebhookConfig struct {
+	Template    string            `json:"template" yaml:"template" toml:"template"`
+	ContentType string            `json:"content_type" yaml:"content_type" toml:"content_type"`
+	Match       map[string]string `json:"match" yaml:"match" toml:"match"`
+	Auth        string            `json:"auth" yaml:"auth" toml:"auth"`
+	Limit       int               `json:"limit" yaml:"limit" toml:"limit"`
+	Period      string            `json:"period" yaml:"period" toml:"period"`
+}
+
+var webhookFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// WebhookWriter posts entries from a background goroutine so Write never waits on the endpoint
+type WebhookWriter struct {
+	url         string
+	body        *template.Template
+	contentType string
+	match       map[string]string
+	auth        string
+	limit       *limiter
+	client      *http.Client
+	ch          chan *Entry
+	flush       chan chan struct{}
+	done        chan struct{}
+	stopped     chan struct{}
+	closeOnce   sync.Once
+	failing     atomic.Bool
+}
+
+func NewWebhookWriter(address string, cfg WebhookConfig) (*WebhookWriter, error) {
+	if target, err := url.Parse(address); err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("webhook: address %q is not an http:// or https:// URL", address)
+	}
+	x := &WebhookWriter{url: address, contentType: cfg.ContentType, match: cfg.Match, auth: os.ExpandEnv(cfg.Auth),
+		client: &http.Client{Timeout: WEBHOOK_TIMEOUT}, ch: make(chan *Entry, WEBHOOK_BUFFER), flush: make(chan chan struct{}), done: make(chan struct{}), stopped: make(chan struct{})}
+	if x.contentType == "" {
+		x.contentType = "application/json"
+	}
+	if cfg.Template != "" {
+		body, err := template.New("webhook").Funcs(webhookFuncs).Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: %w", err)
+		}
+		x.body = body
+	}
+	limit, period := cfg.Limit, WEBHOOK_PERIOD
+	if limit <= 0 {
+		limit = WEBHOOK_LIMIT
+	}
+	if cfg.Period != "" {
+		var err error
+		if period, err = time.ParseDuration(cfg.Period); err != nil || period <= 0 {
+			return nil, fmt.Errorf("webhook: period %q is not a positive duration", cfg.Period)
+		}
+	}
+	x.limit = newLimiter(limit, period)
+	go x.run()
+	return x, nil
+}
+
+// Write queues the entries of p that match, entries over the rate limit or a full buffer are
+// dropped and counted
+func (x *WebhookWriter) Write(p []byte) (int, error) {
+	select {
+	case <-x.done:
+		return 0, errors.New("webhook writer closed")
+	default:
+	}
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		entry, err := ParseEntry(append([]byte{}, line...))
+		if err != nil || !x.matches(entry) {
+			continue
+		}
+		if !x.limit.allow(limitKey{}, nil, time.Now()) {
+			metricDropped.Add(1)
+			continue
+		}
+		select {
+		case x.ch <- entry:
+		default:
+			metricDropped.Add(1)
+		}
+	}
+	return len(p), nil
+}
+
+func (x *WebhookWriter) matches(entry *Entry) bool {
+	for key, want := range x.match {
+		if value, ok := entry.Field(key); !ok || value != want {
+			return false
+		}
+	}
+	return true
+}
+
+func (x *WebhookWriter) run() {
+	defer close(x.stopped)
+	for {
+		select {
+		case entry := <-x.ch:
+			x.post(entry)
+		case reply := <-x.flush:
+			for len(x.ch) > 0 {
+				x.post(<-x.ch)
+			}
+			close(reply)
+		case <-x.done:
+			for len(x.ch) > 0 {
+				x.post(<-x.ch)
+			}
+			return
+		}
+	}
+}
+
+func (x *WebhookWriter) post(entry *Entry) {
+	body := entry.Raw
+	if x.body != nil {
+		buffer := &bytes.Buffer{}
+		if err := x.body.Execute(buffer, entry); err != nil {
+			diagnose("template", x.url, err)
+			metricDropped.Add(1)
+			return
+		}
+		body = buffer.Bytes()
+	}
+	err := x.send(body)
+	if err == nil {
+		if x.failing.Swap(false) {
+			diagnose("recover", x.url, nil)
+		}
+		return
+	}
+	metricDropped.Add(1)
+	if !x.failing.Swap(true) {
+		diagnose("post", x.url, err)
+	}
+}
+
+func (x *WebhookWriter) send(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, x.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", x.contentType)
+	if x.auth != "" {
+		req.Header.Set("Authorization", x.auth)
+	}
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Flush posts what is queued before ctx is done, Logger.Flush calls it so the alert for a Fatal
+// entry goes out before EXIT
+func (x *WebhookWriter) Flush(ctx context.Context) error {
+	return flushLoop(ctx, x.flush, x.stopped, "webhook")
+}
+
+// Close posts what is queued, waiting up to NET_CLOSE_TIMEOUT
+func (x *WebhookWriter) Close() error {
+	x.closeOnce.Do(func() { close(x.done) })
+	select {
+	case <-x.stopped:
+		return nil
+	case <-time.After(NET_CLOSE_TIMEOUT):
+		return errors.New("webhook writer: timed out posting queued entries")
+	}
+}
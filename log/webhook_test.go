@@ -0,0 +1,81 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebhookTemplateAndAuth(t *testing.T) {
+	t.Setenv("HOOK_TOKEN", "hunter2")
+	server, requests := pushServer(t)
+	w, err := NewWebhookWriter(server.URL+"/hook", WebhookConfig{Template: `{"text": {{json .Message}}}`, Auth: "Bearer ${HOOK_TOKEN}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(`{"level":"error","message":"disk \"full\""}` + "\n"))
+	flushWriter(t, w)
+
+	item := push(t, requests)
+	if item.path != "/hook" || item.header.Get("Authorization") != "Bearer hunter2" || item.header.Get("Content-Type") != "application/json" {
+		t.Fatalf("post to %s with %v", item.path, item.header)
+	}
+	if string(item.body) != `{"text": "disk \"full\""}` {
+		t.Fatalf("body: %s", item.body)
+	}
+}
+
+func TestWebhookMatch(t *testing.T) {
+	server, requests := pushServer(t)
+	w, err := NewWebhookWriter(server.URL, WebhookConfig{Match: map[string]string{"component": "billing"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(`{"component":"dns","message":"one"}` + "\n" + `{"component":"billing","message":"two"}` + "\n"))
+	flushWriter(t, w)
+
+	// NOTE without a template the JSON line is posted as is
+	if item := push(t, requests); string(item.body) != `{"component":"billing","message":"two"}` {
+		t.Fatalf("body: %s", item.body)
+	}
+	select {
+	case item := <-requests:
+		t.Fatalf("posted an entry that does not match: %s", item.body)
+	default:
+	}
+}
+
+func TestWebhookRateLimit(t *testing.T) {
+	server, requests := pushServer(t)
+	w, err := NewWebhookWriter(server.URL, WebhookConfig{Limit: 2, Period: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	for i := 0; i < 5; i++ {
+		w.Write([]byte(`{"message":"alert"}` + "\n"))
+	}
+	flushWriter(t, w)
+	push(t, requests)
+	push(t, requests)
+	select {
+	case <-requests:
+		t.Fatal("posted over the limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestWebhookRefusesBadConfig(t *testing.T) {
+	cases := map[string]WebhookConfig{
+		"hooks.slack.com/x":  {},
+		"https://hooks/x":    {Template: "{{.Message"},
+		"https://hooks/x?a=": {Period: "-1s"},
+	}
+	for address, cfg := range cases {
+		if _, err := NewWebhookWriter(address, cfg); err == nil {
+			t.Fatalf("accepted %s %+v", address, cfg)
+		}
+	}
+}
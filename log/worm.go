@@ -0,0 +1,184 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// WORM_SUFFIX names the manifest a WORM file keeps next to itself, see VerifyWORM
+const WORM_SUFFIX = ".worm"
+
+var ErrAppendOnly = errors.New("file is append-only")
+
+// ManifestPath returns the WORM manifest of a log file
+func ManifestPath(path string) string {
+	return path + WORM_SUFFIX
+}
+
+// wormRecord is one line of the manifest, one per rotated file, oldest first
+type wormRecord struct {
+	Time   string `json:"time"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// EnableWORM makes the file write-once: Seek and Truncate are refused, rotation never deletes or
+// overwrites a backup and checks every rename, MaxBackups is ignored, rotated files are made
+// read-only and their size and hash are appended to the manifest
+func (x *RotatingFile) EnableWORM() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.worm = true
+}
+
+func (x *RotatingFile) rotateWORM() error {
+	size, sum, err := hashFile(x.Path)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for exists(x.backup(n + 1)) {
+		n++
+	}
+	for i := n; i >= 0; i-- {
+		if err := renameVerified(x.backup(i), x.backup(i+1)); err != nil {
+			return err
+		}
+		for _, sidecar := range x.sidecars() {
+			if !exists(sidecar(x.backup(i))) {
+				continue
+			}
+			if err := renameVerified(sidecar(x.backup(i)), sidecar(x.backup(i+1))); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.Chmod(x.backup(1), 0400); err != nil {
+		return err
+	}
+	record, _ := json.Marshal(wormRecord{Time: time.Now().Format(time.RFC3339), Size: size, SHA256: sum})
+	fh, err := os.OpenFile(ManifestPath(x.Path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	if _, err := fh.Write(append(record, '\n')); err != nil {
+		return err
+	}
+	return fh.Sync()
+}
+
+// renameVerified refuses to replace an existing file and checks the rename moved every byte
+func renameVerified(from, to string) error {
+	before, err := os.Stat(from)
+	if err != nil {
+		return err
+	}
+	if exists(to) {
+		return fmt.Errorf("rotate %s: %s already exists: %w", from, to, ErrAppendOnly)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	after, err := os.Stat(to)
+	if err != nil {
+		return fmt.Errorf("rotate %s: %w", from, err)
+	}
+	if after.Size() != before.Size() || exists(from) {
+		return fmt.Errorf("rotate %s: rename to %s was not atomic", from, to)
+	}
+	return nil
+}
+
+func hashFile(path string) (int64, string, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer fh.Close()
+	hash := sha256.New()
+	n, err := io.Copy(hash, fh)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// WORMReport is the result of VerifyWORM, File is the first file that failed
+type WORMReport struct {
+	Files   int    `json:"files"`
+	OK      bool   `json:"ok"`
+	File    string `json:"file,omitempty"`
+	Problem string `json:"problem,omitempty"`
+}
+
+// VerifyWORM checks every rotated file of a WORM log against its manifest: each must still exist
+// under the expected name, be read-only and have the recorded size and hash
+func VerifyWORM(path string) (*WORMReport, error) {
+	x := &WORMReport{OK: true}
+	fh, err := os.Open(ManifestPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	records := []wormRecord{}
+	if fh != nil {
+		scanner := bufio.NewScanner(fh)
+		for scanner.Scan() {
+			record := wormRecord{}
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				fh.Close()
+				return x.fail(ManifestPath(path), "manifest entry is not valid json"), nil
+			}
+			records = append(records, record)
+		}
+		fh.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if !exists(path) {
+		return x.fail(path, "live file is missing"), nil
+	}
+	for i, record := range records {
+		name := backupName(path, len(records)-i)
+		x.Files++
+		info, err := os.Stat(name)
+		if err != nil {
+			return x.fail(name, "rotated file is missing"), nil
+		}
+		if info.Mode().Perm()&0222 != 0 {
+			return x.fail(name, "rotated file is writable"), nil
+		}
+		size, sum, err := hashFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if size != record.Size || sum != record.SHA256 {
+			return x.fail(name, "rotated file was modified after rotation"), nil
+		}
+	}
+	if name := backupName(path, len(records)+1); exists(name) {
+		return x.fail(name, "file is not recorded in the manifest"), nil
+	}
+	return x, nil
+}
+
+func (x *WORMReport) fail(file, problem string) *WORMReport {
+	x.OK = false
+	x.File = file
+	x.Problem = problem
+	return x
+}
@@ -0,0 +1,112 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeWORM writes and rotates a WORM file rotations times, returning the path of the live file
+func writeWORM(t *testing.T, rotations int) (string, *RotatingFile) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "a.log")
+	fh, err := NewRotatingFile(path, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { fh.Close() })
+	fh.EnableWORM()
+	for i := 0; i <= rotations; i++ {
+		if i > 0 {
+			if err := fh.Rotate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := fh.Write([]byte(`{"message":"` + string(rune('a'+i)) + `"}` + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path, fh
+}
+
+func TestWORMRoundTrip(t *testing.T) {
+	path, _ := writeWORM(t, 3)
+	report, err := VerifyWORM(path)
+	if err != nil || !report.OK || report.Files != 3 {
+		t.Fatalf("worm files: %+v %v", report, err)
+	}
+	// NOTE MaxBackups is ignored, nothing is deleted
+	for i := 1; i <= 3; i++ {
+		if !exists(backupName(path, i)) {
+			t.Fatalf("%s was removed", backupName(path, i))
+		}
+	}
+}
+
+func TestWORMRefusesSeekAndTruncate(t *testing.T) {
+	_, fh := writeWORM(t, 0)
+	if _, err := fh.Seek(0, io.SeekStart); !errors.Is(err, ErrAppendOnly) {
+		t.Fatalf("seek: %v", err)
+	}
+	if err := fh.Truncate(0); !errors.Is(err, ErrAppendOnly) {
+		t.Fatalf("truncate: %v", err)
+	}
+}
+
+func TestWORMDetectsTampering(t *testing.T) {
+	cases := map[string]struct {
+		edit    func(path string) error
+		problem string
+	}{
+		"modified": {func(path string) error {
+			name := backupName(path, 2)
+			os.Chmod(name, 0600)
+			if err := os.WriteFile(name, []byte(`{"message":"x"}`+"\n"), 0600); err != nil {
+				return err
+			}
+			return os.Chmod(name, 0400)
+		}, "rotated file was modified after rotation"},
+		"truncated": {func(path string) error {
+			name := backupName(path, 2)
+			os.Chmod(name, 0600)
+			if err := os.Truncate(name, 3); err != nil {
+				return err
+			}
+			return os.Chmod(name, 0400)
+		}, "rotated file was modified after rotation"},
+		"writable": {func(path string) error {
+			return os.Chmod(backupName(path, 1), 0600)
+		}, "rotated file is writable"},
+		"deleted": {func(path string) error {
+			return os.Remove(backupName(path, 3))
+		}, "rotated file is missing"},
+		"reordered": {func(path string) error {
+			swap := backupName(path, 1) + ".swap"
+			if err := os.Rename(backupName(path, 1), swap); err != nil {
+				return err
+			}
+			if err := os.Rename(backupName(path, 2), backupName(path, 1)); err != nil {
+				return err
+			}
+			return os.Rename(swap, backupName(path, 2))
+		}, "rotated file was modified after rotation"},
+		"unrecorded": {func(path string) error {
+			return os.WriteFile(backupName(path, 4), []byte(`{"message":"x"}`+"\n"), 0400)
+		}, "file is not recorded in the manifest"},
+	}
+	for name, item := range cases {
+		t.Run(name, func(t *testing.T) {
+			path, _ := writeWORM(t, 3)
+			if err := item.edit(path); err != nil {
+				t.Fatal(err)
+			}
+			report, err := VerifyWORM(path)
+			if err != nil || report.OK || report.Problem != item.problem {
+				t.Fatalf("expected %q: %+v %v", item.problem, report, err)
+			}
+		})
+	}
+}
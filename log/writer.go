@@ -0,0 +1,118 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MultiWriter writes every line to all of its writers, unlike io.MultiWriter a failing writer
+// does not stop the others and the errors are returned together
+type MultiWriter struct {
+	mu      sync.Mutex
+	writers []io.Writer
+}
+
+func NewMultiWriter(writers ...io.Writer) *MultiWriter {
+	return &MultiWriter{writers: writers}
+}
+
+// Add appends a writer, safe while the MultiWriter is in use
+func (x *MultiWriter) Add(w io.Writer) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.writers = append(x.writers, w)
+}
+
+func (x *MultiWriter) Write(p []byte) (int, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	problems := []error{}
+	for _, w := range x.writers {
+		if _, err := w.Write(p); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return len(p), errors.Join(problems...)
+}
+
+// lockedWriter serializes writes, a plain io.Writer like bytes.Buffer is not safe for concurrent use
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (x *lockedWriter) Write(p []byte) (int, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.w.Write(p)
+}
+
+func writerSink(w io.Writer) *sink {
+	return &sink{name: fmt.Sprintf("writer:%T", w), level: LOG_TRACE, w: &lockedWriter{w: w}}
+}
+
+// AddWriter sends every entry to w as well, e.g. a network connection or a test buffer; writers
+// survive a reload and are never closed by the logger
+func AddWriter(w io.Writer) {
+	std.AddWriter(w)
+}
+
+func (x *Logger) AddWriter(w io.Writer) {
+	for {
+		old := x.writers.Load()
+		next := []*sink{writerSink(w)}
+		if old != nil {
+			next = append(append([]*sink{}, *old...), next...)
+		}
+		if x.writers.CompareAndSwap(old, &next) {
+			return
+		}
+	}
+}
+
+// SetOutput makes the writers the only destinations: configured sinks are closed and the default
+// logger stops writing to the InitLogger destinations, a later Apply opens the sinks again
+func SetOutput(writers ...io.Writer) {
+	std.SetOutput(writers...)
+}
+
+func (x *Logger) SetOutput(writers ...io.Writer) {
+	sinks := []*sink{}
+	for _, w := range writers {
+		sinks = append(sinks, writerSink(w))
+	}
+	x.writers.Store(&sinks)
+	for {
+		st := x.current.Load()
+		if st == nil {
+			break
+		}
+		next := *st
+		next.sinks, next.audit, next.components = nil, nil, nil
+		if x.current.CompareAndSwap(st, &next) {
+			st.close()
+			break
+		}
+	}
+	if x.legacy {
+		legacyMu.Lock()
+		defer legacyMu.Unlock()
+		LOG_STDERR = false
+		if LOG_FH != nil {
+			LOG_FH.Close()
+			LOG_FH = nil
+		}
+		LOG_FILE = ""
+	}
+}
+
+// extra returns the writers added with AddWriter or SetOutput
+func (x *Logger) extra() []*sink {
+	if sinks := x.writers.Load(); sinks != nil {
+		return *sinks
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package logr adapts a log.Logger to logr so controller-runtime and other logr based libraries
+// write through sloan, it lives apart from package log so only services that embed them pull in
+// the logr module:
+//
+//	ctrl.SetLogger(logr.New(log.Default()))
+package logr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/osintami/sloan/log"
+)
+
+// NOTE V(0) is info, V(1) debug and V(2) and above trace, so "-v=2" style verbosity maps onto
+// the levels set with SLOAN_LEVELS or SetLevel
+func levelOf(verbosity int) log.Level {
+	switch {
+	case verbosity <= 0:
+		return log.InfoLevel
+	case verbosity == 1:
+		return log.DebugLevel
+	}
+	return log.TraceLevel
+}
+
+// Sink is a logr.LogSink, logger names joined with "/" become the component of the entries so
+// a component level applies to a controller and everything below it
+type Sink struct {
+	root   *log.Logger
+	logger *log.Logger
+	name   string
+	values []interface{}
+	depth  int
+}
+
+// New returns a logr.Logger writing through logger
+func New(logger *log.Logger) logr.Logger {
+	return logr.New(&Sink{root: logger, logger: logger})
+}
+
+func (x *Sink) Init(info logr.RuntimeInfo) {
+	x.depth += info.CallDepth
+}
+
+func (x *Sink) Enabled(verbosity int) bool {
+	return levelOf(verbosity) >= x.logger.GetLevel()
+}
+
+func (x *Sink) Info(verbosity int, msg string, keysAndValues ...interface{}) {
+	var e log.ILogger
+	switch levelOf(verbosity) {
+	case log.InfoLevel:
+		e = x.logger.Info()
+	case log.DebugLevel:
+		e = x.logger.Debug()
+	default:
+		e = x.logger.Trace()
+	}
+	x.write(e, msg, keysAndValues)
+}
+
+func (x *Sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	x.write(x.logger.Error().Err(err), msg, keysAndValues)
+}
+
+func (x *Sink) write(e log.ILogger, msg string, keysAndValues []interface{}) {
+	if event, ok := e.(*log.Event); ok {
+		// NOTE logr.Logger, Info or Error and this method sit between the call site and Msg
+		event.Skip(x.depth + 2)
+	}
+	fields(e, keysAndValues).Msg(msg)
+}
+
+// adder is what log.ILogger and *log.Context have in common, so values given to WithValues and
+// to a single call are written the same way
+type adder[T any] interface {
+	Str(string, string) T
+	Int(string, int) T
+	Int64(string, int64) T
+	Float64(string, float64) T
+	Bool(string, bool) T
+	Dur(string, time.Duration) T
+	Time(string, time.Time) T
+	Any(string, interface{}) T
+}
+
+// fields adds key value pairs, a key without a value is written with a null value
+func fields[T adder[T]](e T, keysAndValues []interface{}) T {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		if i+1 == len(keysAndValues) {
+			e = e.Any(key, nil)
+			break
+		}
+		switch value := keysAndValues[i+1].(type) {
+		case string:
+			e = e.Str(key, value)
+		case int:
+			e = e.Int(key, value)
+		case int64:
+			e = e.Int64(key, value)
+		case float64:
+			e = e.Float64(key, value)
+		case bool:
+			e = e.Bool(key, value)
+		case time.Duration:
+			e = e.Dur(key, value)
+		case time.Time:
+			e = e.Time(key, value)
+		case error:
+			e = e.Str(key, value.Error())
+		case fmt.Stringer:
+			e = e.Str(key, value.String())
+		default:
+			e = e.Any(key, value)
+		}
+	}
+	return e
+}
+
+func (x *Sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return x.derive(x.name, append(append([]interface{}{}, x.values...), keysAndValues...), x.depth)
+}
+
+func (x *Sink) WithName(name string) logr.LogSink {
+	if x.name != "" {
+		name = x.name + "/" + name
+	}
+	return x.derive(name, x.values, x.depth)
+}
+
+// WithCallDepth makes the sink a logr.CallDepthLogSink for helpers that wrap logr
+func (x *Sink) WithCallDepth(depth int) logr.LogSink {
+	return x.derive(x.name, x.values, x.depth+depth)
+}
+
+// derive rebuilds the logger from the root so a name change does not repeat the component
+func (x *Sink) derive(name string, values []interface{}, depth int) *Sink {
+	logger := x.root
+	if name = strings.Trim(name, "/"); name != "" {
+		logger = logger.Component(name)
+	}
+	if len(values) > 0 {
+		logger = fields(logger.With(), values).Logger()
+	}
+	return &Sink{root: x.root, logger: logger, name: name, values: values, depth: depth}
+}
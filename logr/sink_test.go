@@ -0,0 +1,72 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package logr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osintami/sloan/log"
+)
+
+// entries returns a logr.Logger over a logger writing at level and the entries it writes
+func entries(t *testing.T, level string) (*log.Logger, <-chan log.Entry) {
+	t.Helper()
+	logger, err := log.New(log.Config{Level: level, Caller: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logger.Close(context.Background()) })
+	entries, unsubscribe := log.Subscribe(func(x *log.Entry) bool { return x.Message != "startup" })
+	t.Cleanup(unsubscribe)
+	return logger, entries
+}
+
+func received(t *testing.T, entries <-chan log.Entry) log.Entry {
+	t.Helper()
+	select {
+	case entry := <-entries:
+		return entry
+	case <-time.After(time.Second):
+		t.Fatal("no entry")
+	}
+	return log.Entry{}
+}
+
+func TestSinkNamesValuesAndCaller(t *testing.T) {
+	logger, written := entries(t, "info")
+	New(logger).WithName("controller").WithName("pods").WithValues("namespace", "default").Info("reconciled", "pod", "web-1", "dangling")
+
+	entry := received(t, written)
+	if entry.Level != "info" || entry.Message != "reconciled" || entry.Fields["component"] != "controller/pods" {
+		t.Fatalf("entry: %s", entry.Raw)
+	}
+	if entry.Fields["namespace"] != "default" || entry.Fields["pod"] != "web-1" {
+		t.Fatalf("values: %s", entry.Raw)
+	}
+	if value, ok := entry.Fields["dangling"]; !ok || value != nil {
+		t.Fatalf("a key without a value should be null: %s", entry.Raw)
+	}
+	if caller, _ := entry.Field("caller"); !strings.Contains(caller, "sink_test.go") {
+		t.Fatalf("caller %q is not the call site", caller)
+	}
+}
+
+func TestSinkVerbosity(t *testing.T) {
+	logger, written := entries(t, "debug")
+	l := New(logger)
+	if !l.V(1).Enabled() || l.V(2).Enabled() {
+		t.Fatal("V(1) should be debug and V(2) trace")
+	}
+	l.V(2).Info("trace")
+	l.V(1).Info("debug")
+	l.Error(errors.New("boom"), "failed")
+	if entry := received(t, written); entry.Level != "debug" || entry.Message != "debug" {
+		t.Fatalf("expected the V(1) entry: %s", entry.Raw)
+	}
+	if entry := received(t, written); entry.Level != "error" || entry.Fields["error"] != "boom" {
+		t.Fatalf("error entry: %s", entry.Raw)
+	}
+}
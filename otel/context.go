@@ -0,0 +1,31 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package otel makes log.Ctx attach trace_id and span_id from the active OpenTelemetry span of
+// the context, it lives apart from package log so only services that trace pull in the OTel API:
+//
+//	import _ "github.com/osintami/sloan/otel"
+package otel
+
+import (
+	"context"
+
+	"github.com/osintami/sloan/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	log.RegisterContextField("trace_id", TraceID)
+	log.RegisterContextField("span_id", SpanID)
+}
+
+// TraceID is the hex trace ID of the span in ctx, log.WithTraceID still takes precedence
+func TraceID(ctx context.Context) (string, bool) {
+	span := trace.SpanContextFromContext(ctx)
+	return span.TraceID().String(), span.HasTraceID()
+}
+
+// SpanID is the hex span ID of the span in ctx
+func SpanID(ctx context.Context) (string, bool) {
+	span := trace.SpanContextFromContext(ctx)
+	return span.SpanID().String(), span.HasSpanID()
+}
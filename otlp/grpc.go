@@ -0,0 +1,119 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package otlp registers the grpc protocol for log sinks of type "otlp", it lives apart from
+// package log so only services that export over gRPC pull in the gRPC and OTLP modules:
+//
+//	import _ "github.com/osintami/sloan/otlp"
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/osintami/sloan/log"
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	common "go.opentelemetry.io/proto/otlp/common/v1"
+	logs "go.opentelemetry.io/proto/otlp/logs/v1"
+	resource "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func init() {
+	log.RegisterOTLPProtocol("grpc", NewExporter)
+}
+
+// Exporter calls LogsService/Export, the endpoint is host:port or a URL whose https scheme
+// turns on TLS, e.g. otel-collector:4317
+type Exporter struct {
+	conn    *grpc.ClientConn
+	client  collogs.LogsServiceClient
+	headers metadata.MD
+}
+
+func NewExporter(endpoint string, headers map[string]string) (log.OTLPExporter, error) {
+	target, security := endpoint, insecure.NewCredentials()
+	if parsed, err := url.Parse(endpoint); err == nil && parsed.Host != "" {
+		target = parsed.Host
+		if parsed.Scheme == "https" {
+			security = credentials.NewClientTLSFromCert(nil, "")
+		}
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(security))
+	if err != nil {
+		return nil, fmt.Errorf("otlp: %w", err)
+	}
+	return &Exporter{conn: conn, client: collogs.NewLogsServiceClient(conn), headers: metadata.New(headers)}, nil
+}
+
+func (x *Exporter) Export(ctx context.Context, attributes map[string]string, records []log.OTLPRecord) error {
+	scope := &logs.ScopeLogs{Scope: &common.InstrumentationScope{Name: "sloan"}}
+	for _, record := range records {
+		scope.LogRecords = append(scope.LogRecords, &logs.LogRecord{
+			TimeUnixNano:   uint64(record.Time.UnixNano()),
+			SeverityNumber: logs.SeverityNumber(record.SeverityNumber),
+			SeverityText:   record.SeverityText,
+			Body:           &common.AnyValue{Value: &common.AnyValue_StringValue{StringValue: record.Body}},
+			Attributes:     keyValues(record.Attributes),
+			TraceId:        record.TraceID,
+			SpanId:         record.SpanID,
+		})
+	}
+	fields := map[string]interface{}{}
+	for key, value := range attributes {
+		fields[key] = value
+	}
+	request := &collogs.ExportLogsServiceRequest{ResourceLogs: []*logs.ResourceLogs{{
+		Resource:  &resource.Resource{Attributes: keyValues(fields)},
+		ScopeLogs: []*logs.ScopeLogs{scope},
+	}}}
+	_, err := x.client.Export(metadata.NewOutgoingContext(ctx, x.headers), request)
+	return err
+}
+
+// Close releases the connection once the writer is done with it
+func (x *Exporter) Close() error {
+	return x.conn.Close()
+}
+
+func keyValues(fields map[string]interface{}) []*common.KeyValue {
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := []*common.KeyValue{}
+	for _, key := range keys {
+		result = append(result, &common.KeyValue{Key: key, Value: anyValue(fields[key])})
+	}
+	return result
+}
+
+func anyValue(value interface{}) *common.AnyValue {
+	switch v := value.(type) {
+	case string:
+		return &common.AnyValue{Value: &common.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &common.AnyValue{Value: &common.AnyValue_BoolValue{BoolValue: v}}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return &common.AnyValue{Value: &common.AnyValue_IntValue{IntValue: n}}
+		}
+		f, _ := v.Float64()
+		return &common.AnyValue{Value: &common.AnyValue_DoubleValue{DoubleValue: f}}
+	case []interface{}:
+		values := []*common.AnyValue{}
+		for _, item := range v {
+			values = append(values, anyValue(item))
+		}
+		return &common.AnyValue{Value: &common.AnyValue_ArrayValue{ArrayValue: &common.ArrayValue{Values: values}}}
+	case map[string]interface{}:
+		return &common.AnyValue{Value: &common.AnyValue_KvlistValue{KvlistValue: &common.KeyValueList{Values: keyValues(v)}}}
+	}
+	return &common.AnyValue{}
+}
@@ -0,0 +1,84 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/osintami/sloan/log"
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// collector keeps the requests and metadata of the calls to Export
+type collector struct {
+	collogs.UnimplementedLogsServiceServer
+	requests chan *collogs.ExportLogsServiceRequest
+	metadata chan metadata.MD
+}
+
+func (x *collector) Export(ctx context.Context, request *collogs.ExportLogsServiceRequest) (*collogs.ExportLogsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	x.metadata <- md
+	x.requests <- request
+	return &collogs.ExportLogsServiceResponse{}, nil
+}
+
+func TestExporter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	x := &collector{requests: make(chan *collogs.ExportLogsServiceRequest, 1), metadata: make(chan metadata.MD, 1)}
+	collogs.RegisterLogsServiceServer(server, x)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	w, err := log.NewOTLPWriter(listener.Addr().String(), log.OTLPConfig{Protocol: "grpc", Headers: map[string]string{"api-key": "k1"}, Resource: map[string]string{"env": "test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write([]byte(`{"time":"2025-03-09T12:00:00Z","level":"warn","span_id":"00f067aa0ba902b7","attempt":3,"message":"slow"}` + "\n"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if md := <-x.metadata; len(md.Get("api-key")) != 1 || md.Get("api-key")[0] != "k1" {
+		t.Fatalf("metadata: %v", md)
+	}
+	request := <-x.requests
+	resource := request.ResourceLogs[0]
+	found := false
+	for _, item := range resource.Resource.Attributes {
+		found = found || item.Key == "env" && item.Value.GetStringValue() == "test"
+	}
+	if !found {
+		t.Fatalf("resource: %v", resource.Resource.Attributes)
+	}
+	record := resource.ScopeLogs[0].LogRecords[0]
+	if record.SeverityNumber != 13 || record.Body.GetStringValue() != "slow" || len(record.SpanId) != 8 {
+		t.Fatalf("record: %v", record)
+	}
+	if attempt := record.Attributes[0]; attempt.Key != "attempt" || attempt.Value.GetIntValue() != 3 {
+		t.Fatalf("attributes: %v", record.Attributes)
+	}
+}
+
+func TestAnyValue(t *testing.T) {
+	value := anyValue(map[string]interface{}{"ratio": json.Number("0.5"), "tags": []interface{}{"a", true}})
+	fields := value.GetKvlistValue().Values
+	if fields[0].Key != "ratio" || fields[0].Value.GetDoubleValue() != 0.5 {
+		t.Fatalf("double: %v", fields[0])
+	}
+	if tags := fields[1].Value.GetArrayValue().Values; tags[0].GetStringValue() != "a" || !tags[1].GetBoolValue() {
+		t.Fatalf("array: %v", tags)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package prometheus exposes the internal logger metrics as a prometheus.Collector, it lives
+// apart from package log so only services that register it pull in the client library
+package prometheus
+
+import (
+	"github.com/osintami/sloan/log"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector reads log.ReadMetrics and log.Health on every scrape, e.g. alert on
+// rate(sloan_entries_total{level="error"}[5m]) or per component on sloan_component_entries_total
+type Collector struct {
+	entries    *prom.Desc
+	bytes      *prom.Desc
+	sampled    *prom.Desc
+	dropped    *prom.Desc
+	queue      *prom.Desc
+	sinkErrors *prom.Desc
+	sinkUp     *prom.Desc
+	components *prom.Desc
+	sinkBytes  *prom.Desc
+	sinkDrops  *prom.Desc
+}
+
+// NewCollector registers nothing, call prometheus.MustRegister(NewCollector())
+func NewCollector() *Collector {
+	return &Collector{
+		entries:    prom.NewDesc("sloan_entries_total", "Entries written per level.", []string{"level"}, nil),
+		bytes:      prom.NewDesc("sloan_bytes_total", "Encoded size of the entries written.", nil, nil),
+		sampled:    prom.NewDesc("sloan_sampled_total", "Entries dropped by sampling.", nil, nil),
+		dropped:    prom.NewDesc("sloan_dropped_total", "Entries a full subscriber missed.", nil, nil),
+		queue:      prom.NewDesc("sloan_queue_depth", "Entries waiting in subscriber channels.", nil, nil),
+		sinkErrors: prom.NewDesc("sloan_sink_errors_total", "Failed writes per sink.", []string{"sink"}, nil),
+		sinkUp:     prom.NewDesc("sloan_sink_up", "1 when every sink of the name is connected and its last write succeeded.", []string{"sink"}, nil),
+		components: prom.NewDesc("sloan_component_entries_total", "Entries written per configured component and level, other components as \"other\".", []string{"component", "level"}, nil),
+		sinkBytes:  prom.NewDesc("sloan_sink_bytes_total", "Bytes written per sink.", []string{"sink"}, nil),
+		sinkDrops:  prom.NewDesc("sloan_sink_dropped_total", "Entries the async queue of a sink discarded.", []string{"sink"}, nil),
+	}
+}
+
+func (x *Collector) Describe(ch chan<- *prom.Desc) {
+	ch <- x.entries
+	ch <- x.bytes
+	ch <- x.sampled
+	ch <- x.dropped
+	ch <- x.queue
+	ch <- x.sinkErrors
+	ch <- x.sinkUp
+	ch <- x.components
+	ch <- x.sinkBytes
+	ch <- x.sinkDrops
+}
+
+func (x *Collector) Collect(ch chan<- prom.Metric) {
+	metrics := log.ReadMetrics()
+	for level, count := range metrics.Entries {
+		ch <- prom.MustNewConstMetric(x.entries, prom.CounterValue, float64(count), level)
+	}
+	ch <- prom.MustNewConstMetric(x.bytes, prom.CounterValue, float64(metrics.Bytes))
+	ch <- prom.MustNewConstMetric(x.sampled, prom.CounterValue, float64(metrics.Sampled))
+	ch <- prom.MustNewConstMetric(x.dropped, prom.CounterValue, float64(metrics.Dropped))
+	ch <- prom.MustNewConstMetric(x.queue, prom.GaugeValue, float64(metrics.QueueDepth))
+	for name, count := range metrics.SinkErrors {
+		ch <- prom.MustNewConstMetric(x.sinkErrors, prom.CounterValue, float64(count), name)
+	}
+	for component, levels := range metrics.Components {
+		for level, count := range levels {
+			ch <- prom.MustNewConstMetric(x.components, prom.CounterValue, float64(count), component, level)
+		}
+	}
+	for name, count := range metrics.SinkBytes {
+		ch <- prom.MustNewConstMetric(x.sinkBytes, prom.CounterValue, float64(count), name)
+	}
+	for name, count := range metrics.SinkDropped {
+		ch <- prom.MustNewConstMetric(x.sinkDrops, prom.CounterValue, float64(count), name)
+	}
+	// NOTE sink names are not unique, e.g. two AddWriter(&bytes.Buffer{}) or stderr in the root
+	// and a component, and a duplicate series fails the whole scrape; sinks sharing a name are up
+	// only when all of them are
+	up := map[string]float64{}
+	for _, item := range log.Health().Sinks {
+		if _, ok := up[item.Name]; !ok {
+			up[item.Name] = 1
+		}
+		if !item.Connected || item.Failing {
+			up[item.Name] = 0
+		}
+	}
+	for name, value := range up {
+		ch <- prom.MustNewConstMetric(x.sinkUp, prom.GaugeValue, value, name)
+	}
+}
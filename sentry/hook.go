@@ -0,0 +1,73 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package sentry forwards error and fatal entries to Sentry as events, it lives apart from
+// package log so only services that report to Sentry pull in the SDK
+package sentry
+
+import (
+	"reflect"
+	"time"
+
+	sentry "github.com/getsentry/sentry-go"
+	"github.com/osintami/sloan/log"
+)
+
+// NOTE FLUSH_TIMEOUT bounds the wait for fatal and panic entries to reach Sentry before the
+// process goes down
+const FLUSH_TIMEOUT = 2 * time.Second
+
+var LEVELS = map[log.Level]sentry.Level{
+	log.ErrorLevel: sentry.LevelError,
+	log.FatalLevel: sentry.LevelFatal,
+}
+
+// Hook turns entries at Level and above into Sentry events fingerprinted by their message
+// template, so "lookup {host} failed" is one issue however many hosts fail; the entry is
+// still written to the sinks
+type Hook struct {
+	Hub   *sentry.Hub
+	Level log.Level
+}
+
+// NewHook reports errors and fatal entries through the hub set up by sentry.Init, e.g.
+// log.AddHook(sentry.NewHook())
+func NewHook() *Hook {
+	return &Hook{Hub: sentry.CurrentHub(), Level: log.ErrorLevel}
+}
+
+func (x *Hook) Run(e *log.Event, level log.Level, msg string) {
+	if level < x.Level {
+		return
+	}
+	event := sentry.NewEvent()
+	event.Level = LEVELS[level]
+	if event.Level == "" {
+		event.Level = sentry.LevelWarning
+	}
+	// NOTE the fields are masked already, msg and the error text are not
+	msg = e.Redact(msg)
+	event.Message = msg
+	event.Fingerprint = []string{e.Template()}
+	event.Logger = "sloan"
+
+	fields := e.Fields()
+	if component, ok := fields["component"].(string); ok {
+		event.Tags["component"] = component
+	}
+	event.Contexts["fields"] = fields
+
+	// NOTE the stack is taken here, inside Msg, the SDK trims the frames of this module
+	exception := sentry.Exception{Type: e.Template(), Value: msg, Stacktrace: sentry.NewStacktrace()}
+	if err := e.Error(); err != nil {
+		exception.Type, exception.Value = reflect.TypeOf(err).String(), e.Redact(err.Error())
+		if trace := sentry.ExtractStacktrace(err); trace != nil {
+			exception.Stacktrace = trace
+		}
+	}
+	event.Exception = []sentry.Exception{exception}
+
+	x.Hub.CaptureEvent(event)
+	if level == log.FatalLevel {
+		x.Hub.Flush(FLUSH_TIMEOUT)
+	}
+}
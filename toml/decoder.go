@@ -0,0 +1,16 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package toml registers the decoder for config files ending in .toml, it lives apart from
+// package log so only services configured in TOML pull in the parser:
+//
+//	import _ "github.com/osintami/sloan/toml"
+package toml
+
+import (
+	"github.com/BurntSushi/toml"
+	"github.com/osintami/sloan/log"
+)
+
+func init() {
+	log.RegisterConfigDecoder(".toml", toml.Unmarshal)
+}
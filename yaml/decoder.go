@@ -0,0 +1,17 @@
+// Copyright © 2025 Sloan Kendall Childers III
+
+// Package yaml registers the decoder for config files ending in .yaml and .yml, it lives apart
+// from package log so only services configured in YAML pull in the parser:
+//
+//	import _ "github.com/osintami/sloan/yaml"
+package yaml
+
+import (
+	"github.com/osintami/sloan/log"
+	yaml "go.yaml.in/yaml/v3"
+)
+
+func init() {
+	log.RegisterConfigDecoder(".yaml", yaml.Unmarshal)
+	log.RegisterConfigDecoder(".yml", yaml.Unmarshal)
+}
@@ -0,0 +1,25 @@
+// Copyright © 2025 Sloan Kendall Childers III
+package yaml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/osintami/sloan/log"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sloan.yaml")
+	data := "level: warn\nsinks:\n  - type: file\n    path: /var/log/app.log\n    chain: true\nrotation:\n  max_size_mb: 10\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := log.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Level != "warn" || len(cfg.Sinks) != 1 || !cfg.Sinks[0].Chain || cfg.Rotation.MaxSizeMB != 10 {
+		t.Fatalf("config: %+v", cfg)
+	}
+}